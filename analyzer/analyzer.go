@@ -3,13 +3,20 @@ package analyzer
 import "github.com/schollz/progressbar/v3"
 
 func AnalyzeFiles(files []string) []Finding {
+	return AnalyzeFilesWithConfig(files, DefaultConfig())
+}
+
+// AnalyzeFilesWithConfig is AnalyzeFiles with the rule thresholds of cfg.
+func AnalyzeFilesWithConfig(files []string, cfg Config) []Finding {
 	var results []Finding
 	bar := progressbar.Default(int64(len(files)))
 	for _, file := range files {
-		results = append(results, AnalyzeCleanCode(file)...)
+		results = append(results, analyzeCleanCode(file, cfg)...)
 		results = append(results, analyzePerformance(file)...)
 		results = append(results, analyzeSecurity(file)...)
 		bar.Add(1)
 	}
+	results = append(results, analyzeProject(files)...)
+	assignFingerprints(results, cfg.Root)
 	return results
 }
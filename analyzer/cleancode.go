@@ -5,24 +5,33 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"os"
 
+	"github.com/gotech-hub/gocheck/metrics"
 	"github.com/gotech-hub/gocheck/utils"
 )
 
 const (
-	maxFuncLines      = 100
 	maxFuncParams     = 4
-	maxNestingDepth   = 3 // 1 is function body, >3 means nested more than 2 levels
+	maxFuncResults    = 3
+	maxNestingDepth   = 3 // control structures nested in each other, the function body itself is level 0
 	maxReturnStmts    = 2
 	maxIfElseBranches = 3
 	maxLocalVars      = 8
-	minFuncNameLength = 3
 )
 
 func AnalyzeCleanCode(file string) []Finding {
+	return analyzeCleanCode(file, DefaultConfig())
+}
+
+func analyzeCleanCode(file string, cfg Config) []Finding {
 	var results []Finding
+	src, err := os.ReadFile(file)
+	if err != nil {
+		return results
+	}
 	fset := token.NewFileSet()
-	node, err := parser.ParseFile(fset, file, parser.ParseComments, 0)
+	node, err := parser.ParseFile(fset, file, src, parser.ParseComments)
 	if err != nil {
 		return results
 	}
@@ -37,10 +46,12 @@ func AnalyzeCleanCode(file string) []Finding {
 						results = append(results, Finding{
 							File:       file,
 							Line:       pos.Line,
+							Column:     pos.Column,
 							Message:    fmt.Sprintf("Global variable '%s' should be avoided", name.Name),
 							Severity:   Medium,
 							Suggestion: "Avoid using global variables. Use function parameters or struct fields instead.",
 							Category:   "Clean",
+							Rule:       "global-var",
 						})
 					}
 				}
@@ -53,52 +64,23 @@ func AnalyzeCleanCode(file string) []Finding {
 		if ok && fn.Body != nil {
 			pos := fset.Position(fn.Pos())
 
-			// Rule 1: Function is too long
-			if len(fn.Body.List) > maxFuncLines {
-				results = append(results, Finding{
-					File:       file,
-					Line:       pos.Line,
-					Message:    fmt.Sprintf("Function %s is too long (%d lines)", fn.Name.Name, len(fn.Body.List)),
-					Severity:   Medium,
-					Suggestion: "Split the function into smaller functions for better readability and testability.",
-					Category:   "Clean",
-				})
-			}
+			// Rule 1 (function length) runs on function literals too, see analyzeFuncLength
 
-			// Rule 2: Function has too many parameters
-			if fn.Type.Params != nil && len(fn.Type.Params.List) > maxFuncParams {
-				results = append(results, Finding{
-					File:       file,
-					Line:       pos.Line,
-					Message:    fmt.Sprintf("Function %s has too many parameters (%d)", fn.Name.Name, len(fn.Type.Params.List)),
-					Severity:   Medium,
-					Suggestion: "Consider grouping parameters or using a struct.",
-					Category:   "Clean",
-				})
-			}
+			// Rule 2: Function has too many parameters or results, or adjacent bool parameters
+			results = append(results, analyzeParams(file, fset, fn)...)
 
-			// Rule 3: Function is nested too deeply (>2 levels)
-			var maxDepth, currDepth int
-			ast.Inspect(fn.Body, func(n ast.Node) bool {
-				switch n.(type) {
-				case *ast.BlockStmt:
-					currDepth++
-					if currDepth > maxDepth {
-						maxDepth = currDepth
-					}
-					return true
-				default:
-					return true
-				}
-			})
-			if maxDepth > maxNestingDepth {
+			// Rule 3: Function is nested too deeply (>3 levels of if/for/switch/select/func literal)
+			if maxDepth, deepest := metrics.Nesting(fn.Body); maxDepth > maxNestingDepth {
+				deepestPos := fset.Position(deepest)
 				results = append(results, Finding{
 					File:       file,
-					Line:       pos.Line,
-					Message:    fmt.Sprintf("Function %s is nested too deeply (%d levels)", fn.Name.Name, maxDepth),
+					Line:       deepestPos.Line,
+					Column:     deepestPos.Column,
+					Message:    fmt.Sprintf("Function %s is nested too deeply (%d levels, max %d)", fn.Name.Name, maxDepth, maxNestingDepth),
 					Severity:   Medium,
 					Suggestion: "Reduce nesting, split logic into smaller functions.",
 					Category:   "Clean",
+					Rule:       "nesting-depth",
 				})
 			}
 
@@ -114,10 +96,12 @@ func AnalyzeCleanCode(file string) []Finding {
 				results = append(results, Finding{
 					File:       file,
 					Line:       pos.Line,
+					Column:     pos.Column,
 					Message:    fmt.Sprintf("Function %s has too many return statements (%d)", fn.Name.Name, returnCount),
 					Severity:   Low,
 					Suggestion: "Consider simplifying the return flow.",
 					Category:   "Clean",
+					Rule:       "return-count",
 				})
 			}
 
@@ -134,10 +118,12 @@ func AnalyzeCleanCode(file string) []Finding {
 				results = append(results, Finding{
 					File:       file,
 					Line:       pos.Line,
+					Column:     pos.Column,
 					Message:    fmt.Sprintf("Function %s has too many if/else branches (%d)", fn.Name.Name, ifCount),
 					Severity:   Low,
 					Suggestion: "Consider refactoring the conditional logic.",
 					Category:   "Clean",
+					Rule:       "if-branches",
 				})
 			}
 
@@ -166,52 +152,18 @@ func AnalyzeCleanCode(file string) []Finding {
 				results = append(results, Finding{
 					File:       file,
 					Line:       pos.Line,
+					Column:     pos.Column,
 					Message:    fmt.Sprintf("Function %s has too many local variables (%d)", fn.Name.Name, localVarCount),
 					Severity:   Low,
 					Suggestion: "Reduce the number of local variables or split logic into smaller functions.",
 					Category:   "Clean",
+					Rule:       "local-vars",
 				})
 			}
 
-			// Rule 7: Function name is too short
-			if len(fn.Name.Name) < minFuncNameLength {
-				results = append(results, Finding{
-					File:       file,
-					Line:       pos.Line,
-					Message:    fmt.Sprintf("Function name '%s' is too short", fn.Name.Name),
-					Severity:   Low,
-					Suggestion: "Use a more descriptive function name.",
-					Category:   "Clean",
-				})
-			}
+			// Rule 7 (naming) covers every declared name, see analyzeNaming
 
-			// Rule 8: Unused local variables
-			usedVars := make(map[string]bool)
-			ast.Inspect(fn.Body, func(n ast.Node) bool {
-				if ident, ok := n.(*ast.Ident); ok && ident.Obj != nil && ident.Obj.Kind == ast.Var {
-					usedVars[ident.Name] = true
-				}
-				return true
-			})
-			ast.Inspect(fn.Body, func(n ast.Node) bool {
-				if decl, ok := n.(*ast.AssignStmt); ok {
-					for _, lhs := range decl.Lhs {
-						if ident, ok := lhs.(*ast.Ident); ok && ident.Obj != nil && ident.Obj.Kind == ast.Var {
-							if !usedVars[ident.Name] {
-								results = append(results, Finding{
-									File:       file,
-									Line:       pos.Line,
-									Message:    fmt.Sprintf("Local variable '%s' declared but not used", ident.Name),
-									Severity:   Low,
-									Suggestion: "Remove unused local variable.",
-									Category:   "Clean",
-								})
-							}
-						}
-					}
-				}
-				return true
-			})
+			// Rule 8 (unused variables) needs type information, see analyzeUnused
 
 			// Rule 10: Avoid nested function declarations
 			ast.Inspect(fn.Body, func(n ast.Node) bool {
@@ -220,10 +172,12 @@ func AnalyzeCleanCode(file string) []Finding {
 					results = append(results, Finding{
 						File:       file,
 						Line:       innerPos.Line,
+						Column:     innerPos.Column,
 						Message:    fmt.Sprintf("Nested function '%s' should be avoided", innerFn.Name.Name),
 						Severity:   Medium,
 						Suggestion: "Declare functions at the top level, not inside other functions.",
 						Category:   "Clean",
+						Rule:       "nested-func",
 					})
 				}
 				return true
@@ -239,10 +193,12 @@ func AnalyzeCleanCode(file string) []Finding {
 							results = append(results, Finding{
 								File:       file,
 								Line:       magicPos.Line,
+								Column:     magicPos.Column,
 								Message:    fmt.Sprintf("Magic number %s detected", lit.Value),
 								Severity:   Low,
 								Suggestion: "Replace magic numbers with named constants.",
 								Category:   "Clean",
+								Rule:       "magic-number",
 							})
 						}
 					}
@@ -262,26 +218,34 @@ func AnalyzeCleanCode(file string) []Finding {
 				results = append(results, Finding{
 					File:       file,
 					Line:       pos.Line,
+					Column:     pos.Column,
 					Message:    fmt.Sprintf("Function %s has too many comments (%d)", fn.Name.Name, commentCount),
 					Severity:   Low,
 					Suggestion: "Refactor code to be self-explanatory and reduce excessive comments.",
 					Category:   "Clean",
+					Rule:       "comment-count",
 				})
 			}
 
 			// Rule 13: Avoid commented-out code (code that is commented out)
 			if fn.Body != nil && node.Comments != nil {
 				for _, cg := range node.Comments {
+					// chỉ xét comment nằm trong thân hàm, tránh báo lặp lại cho mỗi hàm
+					if cg.Pos() < fn.Body.Lbrace || cg.End() > fn.Body.Rbrace {
+						continue
+					}
 					for _, c := range cg.List {
 						if utils.IsCommentedOutCode(c.Text) {
 							commentPos := fset.Position(c.Pos())
 							results = append(results, Finding{
 								File:       file,
 								Line:       commentPos.Line,
+								Column:     commentPos.Column,
 								Message:    "Commented-out code detected",
 								Severity:   Low,
 								Suggestion: "Remove commented-out code for better readability.",
 								Category:   "Clean",
+								Rule:       "commented-code",
 							})
 						}
 					}
@@ -291,5 +255,12 @@ func AnalyzeCleanCode(file string) []Finding {
 		return true
 	})
 
+	// Rule 1: Function is too long
+	results = append(results, analyzeFuncLength(file, fset, node, src, cfg)...)
+	results = append(results, analyzeComplexity(file, fset, node, cfg)...)
+	// Rule 7: Go naming conventions
+	results = append(results, analyzeNaming(file, fset, node)...)
+	// Rule 16: Doc comments
+	results = append(results, analyzeDocs(file, fset, node)...)
 	return results
 }
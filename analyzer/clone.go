@@ -0,0 +1,368 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"hash/fnv"
+	"sort"
+	"strings"
+)
+
+// minCloneLines is the shortest run of code reported as duplicated.
+const minCloneLines = 6
+
+// minCloneNodes is the least number of AST nodes in a clone, so a long
+// string literal or a few statements spread over many lines is not one.
+const minCloneNodes = 20
+
+// stmtList is a list of statements in which clones are searched: the body
+// of a block, of a case clause or of a select clause.
+type stmtList struct {
+	file   string
+	stmts  []ast.Stmt
+	shapes []string // cloneShape of each statement
+	hashes []uint64 // hash of each shape
+}
+
+// cloneCopy is the run of statements from..to-1 of a list.
+type cloneCopy struct {
+	list     *stmtList
+	from, to int
+}
+
+// next returns the index of the statement k steps past the end of c in
+// direction dir (1 forward, -1 backward), or -1 if there is none.
+func (c cloneCopy) next(dir, k int) int {
+	i := c.to + k - 1
+	if dir < 0 {
+		i = c.from - k
+	}
+	if i < 0 || i >= len(c.list.stmts) {
+		return -1
+	}
+	return i
+}
+
+// grow returns c extended in direction dir up to the statement i.
+func (c cloneCopy) grow(dir, i int) cloneCopy {
+	if dir > 0 {
+		c.to = i + 1
+	} else {
+		c.from = i
+	}
+	return c
+}
+
+func (c cloneCopy) location(fset *token.FileSet) Location {
+	return Location{
+		File:    c.list.file,
+		Line:    fset.Position(c.list.stmts[c.from].Pos()).Line,
+		EndLine: fset.Position(c.list.stmts[c.to-1].End()).Line,
+	}
+}
+
+// sameCode compares the copies statement by statement, it confirms what
+// equal hashes suggest.
+func sameCode(a, b cloneCopy) bool {
+	if a.to-a.from != b.to-b.from {
+		return false
+	}
+	for i := 0; i < a.to-a.from; i++ {
+		if a.list.shapes[a.from+i] != b.list.shapes[b.from+i] {
+			return false
+		}
+	}
+	return true
+}
+
+// overlapping reports whether two of the copies share statements.
+func overlapping(copies []cloneCopy) bool {
+	for i, a := range copies {
+		for _, b := range copies[i+1:] {
+			if a.list == b.list && a.from < b.to && b.from < a.to {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// extend grows every copy in direction dir while the next statements of all
+// copies have the same shape, and returns the number of statements added.
+func extend(copies []cloneCopy, dir int) int {
+	n := 0
+	grown := make([]cloneCopy, len(copies))
+	for {
+		first := copies[0].next(dir, 1)
+		if first < 0 {
+			return n
+		}
+		for k, c := range copies {
+			i := c.next(dir, 1)
+			if i < 0 || c.list.shapes[i] != copies[0].list.shapes[first] {
+				return n
+			}
+			grown[k] = c.grow(dir, i)
+		}
+		if overlapping(grown) {
+			return n
+		}
+		copy(copies, grown)
+		n++
+	}
+}
+
+// bridge extends a pair of copies over one statement added to one of them,
+// or changed, when more than one matching statement follows. It makes near
+// duplicates one clone instead of two shorter ones.
+func bridge(pair []cloneCopy, dir int) bool {
+	for _, skip := range [][2]int{{2, 1}, {1, 2}, {2, 2}} {
+		a, b := pair[0].next(dir, skip[0]), pair[1].next(dir, skip[1])
+		if a < 0 || b < 0 || pair[0].list.shapes[a] != pair[1].list.shapes[b] {
+			continue
+		}
+		trial := []cloneCopy{pair[0].grow(dir, a), pair[1].grow(dir, b)}
+		if overlapping(trial) || extend(trial, dir) == 0 {
+			continue
+		}
+		copy(pair, trial)
+		return true
+	}
+	return false
+}
+
+// clone is a group of copies of the same code.
+type clone struct {
+	locs []Location
+	size int  // length of the normalized code, larger clones are reported first
+	near bool // one statement differs between the two copies
+}
+
+// cloneShape writes the shape of n: node types, operators and literal kinds
+// but not identifier names or literal values, so a copy with renamed
+// variables or other constants has the same shape.
+func cloneShape(n ast.Node) string {
+	var b strings.Builder
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case nil:
+			b.WriteByte(')')
+			return false
+		case *ast.CommentGroup:
+			return false
+		case *ast.BasicLit:
+			fmt.Fprint(&b, n.Kind)
+		case *ast.BinaryExpr:
+			fmt.Fprint(&b, n.Op)
+		case *ast.UnaryExpr:
+			fmt.Fprint(&b, n.Op)
+		case *ast.AssignStmt:
+			fmt.Fprint(&b, n.Tok)
+		case *ast.IncDecStmt:
+			fmt.Fprint(&b, n.Tok)
+		case *ast.BranchStmt:
+			fmt.Fprint(&b, n.Tok)
+		case *ast.RangeStmt:
+			fmt.Fprint(&b, n.Tok)
+		case *ast.ChanType:
+			fmt.Fprint(&b, n.Dir)
+		}
+		fmt.Fprintf(&b, "(%T", n)
+		return true
+	})
+	return b.String()
+}
+
+// flatStmt reports whether stmt holds no other statement.
+func flatStmt(stmt ast.Stmt) bool {
+	switch stmt.(type) {
+	case *ast.ExprStmt, *ast.AssignStmt, *ast.DeclStmt, *ast.IncDecStmt, *ast.ReturnStmt, *ast.BranchStmt, *ast.SendStmt, *ast.EmptyStmt:
+		return true
+	}
+	return false
+}
+
+// stmtLists returns the statement lists of a file with the shapes of their
+// statements.
+func stmtLists(path string, node *ast.File) []*stmtList {
+	var lists []*stmtList
+	add := func(stmts []ast.Stmt) {
+		if len(stmts) == 0 {
+			return
+		}
+		l := &stmtList{file: path, stmts: stmts}
+		for _, stmt := range stmts {
+			shape := cloneShape(stmt)
+			h := fnv.New64a()
+			h.Write([]byte(shape))
+			l.shapes = append(l.shapes, shape)
+			l.hashes = append(l.hashes, h.Sum64())
+		}
+		lists = append(lists, l)
+	}
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.BlockStmt:
+			add(n.List)
+		case *ast.CaseClause:
+			add(n.Body)
+		case *ast.CommClause:
+			add(n.Body)
+		}
+		return true
+	})
+	return lists
+}
+
+// analyzeClones reports runs of statements of at least minCloneLines lines
+// that appear more than once in the project, in one file or across files.
+// Every window of statements that spans minCloneLines lines and
+// minCloneNodes nodes, other than a listing of one-line statements, is
+// hashed by its normalized AST (see cloneShape), so copies that only differ
+// in names and literal values are found too, and windows with the same hash
+// are compared statement by statement before they count as copies. A match is
+// then extended as far as the copies go; between two copies one added,
+// removed or changed statement is allowed (near duplicates). A clone inside
+// a larger clone reported with the same copies is not reported again. Test
+// and generated files are skipped.
+func analyzeClones(p *project) []Finding {
+	seeds := map[uint64][]cloneCopy{}
+	for _, f := range p.files {
+		if f.test || f.generated {
+			continue
+		}
+		for _, l := range stmtLists(f.path, f.node) {
+			for i := range l.stmts {
+				start := p.fset.Position(l.stmts[i].Pos()).Line
+				h := fnv.New64a()
+				nodes, mixed, listing := 0, false, true
+				for j := i; j < len(l.stmts); j++ {
+					fmt.Fprintf(h, "%x;", l.hashes[j])
+					nodes += strings.Count(l.shapes[j], "(")
+					mixed = mixed || l.hashes[j] != l.hashes[i]
+					listing = listing && flatStmt(l.stmts[j]) && p.fset.Position(l.stmts[j].End()).Line == p.fset.Position(l.stmts[j].Pos()).Line
+					if p.fset.Position(l.stmts[j].End()).Line-start+1 >= minCloneLines && nodes >= minCloneNodes {
+						// a run of statements of one shape, like case clauses,
+						// repeats itself, and a run of one-line simple
+						// statements, like the Println of a help text, is a
+						// listing rather than logic
+						if !listing && (j == i || mixed) {
+							seeds[h.Sum64()] = append(seeds[h.Sum64()], cloneCopy{list: l, from: i, to: j + 1})
+						}
+						break
+					}
+				}
+			}
+		}
+	}
+
+	var clones []clone
+	for _, windows := range seeds {
+		if len(windows) < 2 {
+			continue
+		}
+		// equal hashes are confirmed on the code itself
+		var groups [][]cloneCopy
+		for _, w := range windows {
+			placed := false
+			for k, g := range groups {
+				if sameCode(g[0], w) && !overlapping(append([]cloneCopy{w}, g...)) {
+					groups[k] = append(g, w)
+					placed = true
+					break
+				}
+			}
+			if !placed {
+				groups = append(groups, []cloneCopy{w})
+			}
+		}
+		for _, copies := range groups {
+			if len(copies) < 2 {
+				continue
+			}
+			extend(copies, 1)
+			extend(copies, -1)
+			var c clone
+			if len(copies) == 2 {
+				c.near = bridge(copies, 1) || bridge(copies, -1)
+			}
+			for _, cp := range copies {
+				c.locs = append(c.locs, cp.location(p.fset))
+			}
+			for i := copies[0].from; i < copies[0].to; i++ {
+				c.size += len(copies[0].list.shapes[i])
+			}
+			clones = append(clones, c)
+		}
+	}
+	sort.Slice(clones, func(i, j int) bool {
+		if clones[i].size != clones[j].size {
+			return clones[i].size > clones[j].size
+		}
+		return lessLocation(clones[i].locs[0], clones[j].locs[0])
+	})
+
+	var reported []Location
+	var results []Finding
+	for _, c := range clones {
+		// a clone whose every copy is part of a reported clone adds nothing
+		inside := true
+		for _, loc := range c.locs {
+			contained := false
+			for _, outer := range reported {
+				if loc.File == outer.File && loc.Line >= outer.Line && loc.EndLine <= outer.EndLine {
+					contained = true
+					break
+				}
+			}
+			if !contained {
+				inside = false
+				break
+			}
+		}
+		if inside {
+			continue
+		}
+
+		locs := c.locs
+		sort.Slice(locs, func(i, j int) bool { return lessLocation(locs[i], locs[j]) })
+		var others []string
+		for _, loc := range locs[1:] {
+			others = append(others, fmt.Sprintf("%s:%d-%d", loc.File, loc.Line, loc.EndLine))
+		}
+		reported = append(reported, locs...)
+		first := locs[0]
+		lines := first.EndLine - first.Line + 1
+		severity := Low
+		if lines >= 3*minCloneLines {
+			severity = Medium
+		}
+		message := fmt.Sprintf("Duplicated code: %d lines repeated in %d places", lines, len(locs))
+		if c.near {
+			message = fmt.Sprintf("Nearly duplicated code: %d lines repeated in %d places, one statement differs", lines, len(locs))
+		}
+		results = append(results, Finding{
+			File:       first.File,
+			Line:       first.Line,
+			EndLine:    first.EndLine,
+			Message:    message,
+			Severity:   severity,
+			Suggestion: "Extract the repeated code into a shared function. Other copies: " + strings.Join(others, ", ") + ".",
+			Category:   "Duplication",
+			Rule:       "duplicate-code",
+			Related:    locs[1:],
+		})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return lessLocation(Location{File: results[i].File, Line: results[i].Line}, Location{File: results[j].File, Line: results[j].Line})
+	})
+	return results
+}
+
+func lessLocation(a, b Location) bool {
+	if a.File != b.File {
+		return a.File < b.File
+	}
+	return a.Line < b.Line
+}
@@ -0,0 +1,121 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const cloneSrc = `package cl
+
+import "fmt"
+
+func one(xs []int) int {
+	total := 0
+	for _, x := range xs {
+		total += x
+	}
+	fmt.Println("sum", total)
+	avg := total / len(xs)
+	fmt.Println("avg", avg)
+	max := 0
+	for _, x := range xs {
+		if x > max {
+			max = x
+		}
+	}
+	return max + avg
+}
+
+func two(ys []int) int {
+	sum := 0
+	for _, y := range ys {
+		sum += y
+	}
+	fmt.Println("sum", sum)
+	mean := sum / len(ys)
+	fmt.Println("extra")
+	fmt.Println("avg", mean)
+	top := 0
+	for _, y := range ys {
+		if y > top {
+			top = y
+		}
+	}
+	return top + mean
+}
+
+func three(a, b int) {
+	if a > b {
+		fmt.Println("different prefix")
+		n := a * 2
+		m := b * 3
+		fmt.Println(n, m)
+		if n > m {
+			fmt.Println("n wins")
+		}
+		fmt.Println(n - m)
+	}
+}
+
+func four(a, b int) {
+	for i := 0; i < a; i++ {
+		fmt.Println("in a loop", i)
+	}
+	c := a * 2
+	d := b * 3
+	fmt.Println(c, d)
+	if c > d {
+		fmt.Println("c wins")
+	}
+	fmt.Println(c - d)
+}
+
+func help() {
+	fmt.Println("usage:")
+	fmt.Println("  a")
+	fmt.Println("  b")
+	fmt.Println("  c")
+	fmt.Println("  d")
+	fmt.Println("  e")
+	fmt.Println("")
+	fmt.Println("flags:")
+	fmt.Println("  a")
+	fmt.Println("  b")
+	fmt.Println("  c")
+	fmt.Println("  d")
+	fmt.Println("  e")
+}
+`
+
+func TestAnalyzeClones(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "cl.go")
+	if err := os.WriteFile(file, []byte(cloneSrc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		line, endLine int
+		near          bool
+		copyLine      int
+	}{
+		{6, 19, true, 23},   // one and two, two has an extra statement
+		{43, 49, false, 57}, // a run of statements inside two different blocks
+	}
+	got := analyzeClones(loadProject([]string{file}))
+	if len(got) != len(tests) {
+		for _, f := range got {
+			t.Logf("%d-%d %s", f.Line, f.EndLine, f.Message)
+		}
+		t.Fatalf("got %d clones, want %d", len(got), len(tests))
+	}
+	for i, tt := range tests {
+		f := got[i]
+		if f.Line != tt.line || f.EndLine != tt.endLine || len(f.Related) != 1 || f.Related[0].Line != tt.copyLine {
+			t.Errorf("clone %d: got %d-%d with copies %v, want %d-%d with a copy at %d", i, f.Line, f.EndLine, f.Related, tt.line, tt.endLine, tt.copyLine)
+		}
+		if near := strings.HasPrefix(f.Message, "Nearly"); near != tt.near {
+			t.Errorf("clone %d: near = %v, want %v (%s)", i, near, tt.near, f.Message)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"github.com/gotech-hub/gocheck/metrics"
+)
+
+// analyzeComplexity reports functions and function literals whose cyclomatic
+// or cognitive complexity exceeds the configured thresholds. The finding
+// lists the constructs that make up the score.
+func analyzeComplexity(file string, fset *token.FileSet, node *ast.File, cfg Config) []Finding {
+	var results []Finding
+	for _, fn := range funcUnits(fset, node) {
+		pos := fset.Position(fn.Pos)
+
+		// Rule 14: Cyclomatic complexity
+		if cc, parts := metrics.CyclomaticDetail(fset, fn.Body); cc > cfg.MaxCyclomatic {
+			results = append(results, Finding{
+				File:       file,
+				Line:       pos.Line,
+				Column:     pos.Column,
+				Message:    fmt.Sprintf("Function %s has cyclomatic complexity %d (max %d)", fn.Name, cc, cfg.MaxCyclomatic),
+				Severity:   complexitySeverity(cc, cfg.MaxCyclomatic),
+				Suggestion: "Split the function or replace branches with lookups. Decision points: " + metrics.Describe(parts) + ".",
+				Category:   "Clean",
+				Rule:       "cyclomatic-complexity",
+			})
+		}
+
+		// Rule 15: Cognitive complexity
+		if cc, parts := metrics.Cognitive(fset, recursiveName(fn.Decl), fn.Body); cc > cfg.MaxCognitive {
+			results = append(results, Finding{
+				File:       file,
+				Line:       pos.Line,
+				Column:     pos.Column,
+				Message:    fmt.Sprintf("Function %s has cognitive complexity %d (max %d)", fn.Name, cc, cfg.MaxCognitive),
+				Severity:   complexitySeverity(cc, cfg.MaxCognitive),
+				Suggestion: "Flatten nested code with early returns and extract nested blocks into functions. Increments: " + metrics.Describe(parts) + ".",
+				Category:   "Clean",
+				Rule:       "cognitive-complexity",
+			})
+		}
+	}
+	return results
+}
+
+// recursiveName returns how a recursive call of decl is written: "walk" for
+// a function, "s.walk" for a method with receiver s, and "" for function
+// literals and methods without a named receiver.
+func recursiveName(decl *ast.FuncDecl) string {
+	if decl == nil {
+		return ""
+	}
+	if decl.Recv == nil {
+		return decl.Name.Name
+	}
+	if len(decl.Recv.List) == 0 || len(decl.Recv.List[0].Names) == 0 || decl.Recv.List[0].Names[0].Name == "_" {
+		return ""
+	}
+	return decl.Recv.List[0].Names[0].Name + "." + decl.Name.Name
+}
+
+// complexitySeverity is Medium above the threshold and High above twice it.
+func complexitySeverity(value, limit int) Severity {
+	if value > 2*limit {
+		return High
+	}
+	return Medium
+}
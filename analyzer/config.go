@@ -0,0 +1,40 @@
+package analyzer
+
+import "fmt"
+
+// Ways to measure the length of a function for Rule 1.
+const (
+	FuncLengthPhysical   = "physical"   // every line from the func keyword to the closing brace, nested literals excluded
+	FuncLengthLogical    = "logical"    // lines holding code, not blank or comment-only
+	FuncLengthStatements = "statements" // statements at any depth, blocks excluded
+)
+
+// Config holds the thresholds of the rules that can be tuned from the command
+// line. The zero value is not useful, start from DefaultConfig.
+type Config struct {
+	MaxCyclomatic int    // cyclomatic complexity above which a function is reported
+	MaxCognitive  int    // cognitive complexity above which a function is reported
+	FuncLength    string // how function length is measured, one of the FuncLength constants
+	MaxFuncLength int    // length above which a function is reported, in FuncLength units
+	Root          string // directory fingerprints are relative to, usually the repository top level
+}
+
+// DefaultConfig returns the thresholds used by AnalyzeFiles.
+func DefaultConfig() Config {
+	return Config{
+		MaxCyclomatic: 10,
+		MaxCognitive:  15,
+		FuncLength:    FuncLengthLogical,
+		MaxFuncLength: 100,
+	}
+}
+
+// Validate reports an error for settings the analyzers cannot use.
+func (c Config) Validate() error {
+	switch c.FuncLength {
+	case FuncLengthPhysical, FuncLengthLogical, FuncLengthStatements:
+	default:
+		return fmt.Errorf("unknown function length metric %q, expected physical, logical or statements", c.FuncLength)
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"sort"
+
+	"github.com/gotech-hub/gocheck/metrics"
+)
+
+// objectKind names the kind of a declared object in messages.
+func objectKind(obj types.Object) string {
+	switch obj := obj.(type) {
+	case *types.Func:
+		if obj.Type().(*types.Signature).Recv() != nil {
+			return "method"
+		}
+		return "function"
+	case *types.TypeName:
+		return "type"
+	case *types.Const:
+		return "constant"
+	case *types.Var:
+		if obj.IsField() {
+			return "field"
+		}
+		return "variable"
+	}
+	return "identifier"
+}
+
+// origin returns the generic declaration of an instantiated function or
+// field, which is the object found in Defs.
+func origin(obj types.Object) types.Object {
+	switch obj := obj.(type) {
+	case *types.Func:
+		return obj.Origin()
+	case *types.Var:
+		return obj.Origin()
+	}
+	return obj
+}
+
+// analyzeDeadCode implements Rule 17, dead code: unexported functions,
+// methods, types, constants and struct fields never referenced in their
+// package, and exported identifiers of internal packages never referenced
+// anywhere in the module, methods and struct fields included. References
+// from test files count. main and init, blank names, methods an interface
+// may call, fields with a struct tag (read by reflection) and declarations
+// in test or generated files are not reported.
+func analyzeDeadCode(p *project) []Finding {
+	pkgs := p.typecheck()
+	ifaces := interfacesOf(pkgs)
+	files := map[string]projectFile{}
+	for _, f := range p.files {
+		files[f.path] = f
+	}
+
+	used := map[types.Object]bool{}
+	tagged := map[types.Object]bool{} // fields with a struct tag, read by reflection
+	ifaceMethods := map[string]bool{} // package path + method name of interface methods
+	for _, tp := range pkgs {
+		for _, obj := range tp.info.Uses {
+			used[origin(obj)] = true
+		}
+		for _, obj := range tp.info.Defs {
+			if fn, ok := obj.(*types.Func); ok {
+				if recv := fn.Type().(*types.Signature).Recv(); recv != nil && types.IsInterface(recv.Type()) {
+					ifaceMethods[tp.path+"."+fn.Name()] = true
+				}
+			}
+		}
+		// T{a, b} sets every field of T without naming them
+		for _, f := range tp.files {
+			ast.Inspect(f.node, func(n ast.Node) bool {
+				if field, ok := n.(*ast.Field); ok && field.Tag != nil {
+					for _, id := range field.Names {
+						tagged[tp.info.Defs[id]] = true
+					}
+				}
+				lit, ok := n.(*ast.CompositeLit)
+				if !ok || len(lit.Elts) == 0 {
+					return true
+				}
+				if _, keyed := lit.Elts[0].(*ast.KeyValueExpr); keyed {
+					return true
+				}
+				if st, ok := tp.info.TypeOf(lit).Underlying().(*types.Struct); ok {
+					for i := 0; i < st.NumFields(); i++ {
+						used[origin(st.Field(i))] = true
+					}
+				}
+				return true
+			})
+		}
+	}
+
+	var results []Finding
+	report := func(obj types.Object, rule, message, suggestion string) {
+		results = append(results, newFinding(p.fset, p.fset.Position(obj.Pos()).Filename, obj.Pos(), Low, "Clean", rule, message, suggestion))
+	}
+	for _, tp := range pkgs {
+		scope := tp.pkg.Scope()
+		for id, obj := range tp.info.Defs {
+			f := files[p.fset.Position(id.Pos()).Filename]
+			if obj == nil || obj.Name() == "_" || used[obj] || f.test || f.generated {
+				continue
+			}
+			packageLevel := obj.Parent() == scope
+			if obj.Exported() {
+				// Rule 17b: Exported identifiers of internal packages are used in the module
+				if tp.internal() && (packageLevel || exportedMember(obj, tagged, ifaces)) {
+					name := obj.Name()
+					if fn, ok := findFunc(f.node, id); ok {
+						name = metrics.FuncName(fn)
+					}
+					report(obj, "unused-exported", fmt.Sprintf("Exported %s %s of internal package %s is never used in the module", objectKind(obj), name, tp.path),
+						"Only this module can import an internal package; remove it or unexport it.")
+				}
+				continue
+			}
+
+			// Rule 17a: Unexported identifiers are used in their package
+			switch obj := obj.(type) {
+			case *types.Func:
+				recv := obj.Type().(*types.Signature).Recv()
+				if recv == nil && (obj.Name() == "main" || obj.Name() == "init") {
+					continue
+				}
+				if recv != nil && (types.IsInterface(recv.Type()) || ifaceMethods[tp.path+"."+obj.Name()]) {
+					continue
+				}
+				name := obj.Name()
+				if fn, ok := findFunc(f.node, id); ok {
+					name = metrics.FuncName(fn)
+				}
+				report(obj, "dead-code", fmt.Sprintf("Unused %s %s", objectKind(obj), name),
+					"Remove it, it is never called in its package.")
+			case *types.TypeName, *types.Const:
+				if !packageLevel {
+					continue
+				}
+				report(obj, "dead-code", fmt.Sprintf("Unused %s %s", objectKind(obj), obj.Name()),
+					"Remove it, it is never referenced in its package.")
+			case *types.Var:
+				if !obj.IsField() || obj.Embedded() {
+					continue
+				}
+				report(obj, "dead-code", fmt.Sprintf("Unused field %s", obj.Name()),
+					"Remove it, it is never read or written in its package.")
+			}
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return lessLocation(Location{File: results[i].File, Line: results[i].Line}, Location{File: results[j].File, Line: results[j].Line})
+	})
+	return results
+}
+
+// exportedMember reports whether obj is a method or a field that Rule 17b
+// checks: methods that may implement an interface are called through it,
+// and tagged fields are read by encoding packages through reflection.
+func exportedMember(obj types.Object, tagged map[types.Object]bool, ifaces []*types.Interface) bool {
+	switch obj := obj.(type) {
+	case *types.Func:
+		recv := obj.Type().(*types.Signature).Recv()
+		return recv != nil && !types.IsInterface(recv.Type()) && !implementsInterface(obj, ifaces)
+	case *types.Var:
+		return obj.IsField() && !obj.Embedded() && !tagged[obj]
+	}
+	return false
+}
+
+// findFunc returns the function declaration named by id.
+func findFunc(node *ast.File, id *ast.Ident) (*ast.FuncDecl, bool) {
+	for _, decl := range node.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name == id {
+			return fn, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,183 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/gotech-hub/gocheck/metrics"
+)
+
+// docWord matches the words of a doc comment that may name an identifier.
+var docWord = regexp.MustCompile("`?[A-Za-z_][A-Za-z0-9_]*`?")
+
+// analyzeDocs implements Rule 16, doc comments: exported declarations are
+// documented, the comment starts with the declared name, and the parameter
+// names it mentions still exist. Test and generated files are skipped.
+func analyzeDocs(file string, fset *token.FileSet, node *ast.File) []Finding {
+	if strings.HasSuffix(file, "_test.go") || ast.IsGenerated(node) {
+		return nil
+	}
+	var results []Finding
+	report := func(pos token.Pos, rule, message, suggestion string) {
+		results = append(results, newFinding(fset, file, pos, Low, "Clean", rule, message, suggestion))
+	}
+
+	// package main is not imported, its exported names need no doc
+	if metrics.DocsRequired(file, node) {
+		for _, d := range metrics.ExportedDecls(node) {
+			name := d.Ident.Name
+			// Rule 16a: Exported declarations have a doc comment
+			if d.Doc == nil {
+				report(d.Ident.Pos(), "missing-doc", fmt.Sprintf("Exported %s %s has no doc comment", d.Kind, d.Name),
+					fmt.Sprintf("Add a comment starting with '%s ...' that says what it does.", name))
+				continue
+			}
+			// Rule 16b: The comment starts with the name, a group comment describes the group
+			if d.Group || startsWithName(d.Doc.Text(), name, d.Kind == "type") {
+				continue
+			}
+			report(d.Doc.Pos(), "doc-prefix", fmt.Sprintf("Doc comment of %s %s should start with '%s'", d.Kind, d.Name, name),
+				fmt.Sprintf("Go doc comments begin with the declared name, e.g. '%s …', so they read well in go doc.", name))
+		}
+	}
+
+	// Rule 16c: Parameter names mentioned in the comment exist in the signature.
+	// A word is taken for a parameter when it is written like one (camelCase
+	// or in backquotes), is not given as an example, and the file has no
+	// identifier or string of that name.
+	idents := map[string]bool{}
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Ident:
+			idents[n.Name] = true
+		case *ast.BasicLit:
+			if n.Kind == token.STRING {
+				for _, word := range docWord.FindAllString(n.Value, -1) {
+					idents[word] = true
+				}
+			}
+		}
+		return true
+	})
+	for _, decl := range node.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Doc == nil {
+			continue
+		}
+		text := fn.Doc.Text()
+		reported := map[string]bool{}
+		for _, loc := range docWord.FindAllStringIndex(text, -1) {
+			word := text[loc[0]:loc[1]]
+			quoted := strings.HasPrefix(word, "`") && strings.HasSuffix(word, "`") && len(word) > 2
+			word = strings.Trim(word, "`")
+			// selectors and paths such as fmt.Sprintf or pkg/fooBar name other things
+			if loc[0] > 0 && strings.ContainsRune("./", rune(text[loc[0]-1])) || isExample(text[:loc[0]]) {
+				continue
+			}
+			if word == "" || !unicode.IsLower(rune(word[0])) || idents[word] || reported[word] {
+				continue
+			}
+			if !quoted && !isCamelCase(word) {
+				continue
+			}
+			reported[word] = true
+			report(fn.Doc.Pos(), "stale-doc-param", fmt.Sprintf("Doc comment of %s mentions '%s', which is not a parameter", metrics.FuncName(fn), word),
+				fmt.Sprintf("Update the comment to the current signature, parameters: %s.", paramList(fn)))
+		}
+	}
+	return results
+}
+
+// startsWithName reports whether a doc comment starts with name. Types may
+// start with an article: "A Finding is …".
+func startsWithName(text, name string, article bool) bool {
+	if strings.HasPrefix(text, "Deprecated:") {
+		return true
+	}
+	words := strings.Fields(text)
+	if article && len(words) > 1 && (words[0] == "A" || words[0] == "An" || words[0] == "The") {
+		words = words[1:]
+	}
+	return len(words) > 0 && strings.TrimSuffix(strings.TrimRight(words[0], ".,:;"), "'s") == name
+}
+
+// isCamelCase reports whether word is a lower-case word with an upper-case
+// letter inside, like userID, which English text does not have.
+func isCamelCase(word string) bool {
+	for _, r := range word[1:] {
+		if unicode.IsUpper(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// isExample reports whether the text before a word introduces an example.
+func isExample(before string) bool {
+	for _, intro := range []string{"e.g. ", "like ", "as in ", "such as "} {
+		if strings.HasSuffix(before, intro) {
+			return true
+		}
+	}
+	return false
+}
+
+// paramList returns the parameter names of fn for a suggestion.
+func paramList(fn *ast.FuncDecl) string {
+	params := flattenFields(fn.Type.Params)
+	if len(params) == 0 {
+		return "none"
+	}
+	return paramNames(params)
+}
+
+// analyzeDocPackages reports packages without a package comment, and
+// package comments not starting with "Package name". A package comment in
+// any file of the directory is enough.
+func analyzeDocPackages(proj *project) []Finding {
+	type pkgDoc struct {
+		file string    // first file of the package, where a missing comment is reported
+		pos  token.Pos // its package clause
+		name string
+		doc  bool
+	}
+	var dirs []string
+	pkgs := map[string]*pkgDoc{}
+	var results []Finding
+	for _, f := range proj.files {
+		if f.test || f.generated {
+			continue
+		}
+		file, node := f.path, f.node
+		p, ok := pkgs[f.dir]
+		if !ok {
+			p = &pkgDoc{file: file, pos: node.Package, name: node.Name.Name}
+			pkgs[f.dir] = p
+			dirs = append(dirs, f.dir)
+		}
+		if node.Doc == nil {
+			continue
+		}
+		p.doc = true
+		want := "Package " + node.Name.Name
+		if node.Name.Name != "main" && !strings.HasPrefix(node.Doc.Text(), want+" ") && !strings.HasPrefix(node.Doc.Text(), want+"\n") {
+			results = append(results, newFinding(proj.fset, file, node.Doc.Pos(), Low, "Clean", "package-doc",
+				fmt.Sprintf("Package comment of %s should start with '%s'", node.Name.Name, want),
+				fmt.Sprintf("Begin the package comment with '%s provides …'.", want)))
+		}
+	}
+	for _, dir := range dirs {
+		p := pkgs[dir]
+		if p.doc {
+			continue
+		}
+		results = append(results, newFinding(proj.fset, p.file, p.pos, Low, "Clean", "package-doc",
+			fmt.Sprintf("Package %s has no package comment", p.name),
+			fmt.Sprintf("Add a comment 'Package %s …' above the package clause of one file, usually doc.go.", p.name)))
+	}
+	return results
+}
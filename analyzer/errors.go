@@ -0,0 +1,325 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// errorType is the predeclared error interface.
+var errorType = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+// uncheckedOK lists the functions whose error result is conventionally not
+// checked: printing to stdout, and csv writes whose error is kept for
+// Flush and Error.
+var uncheckedOK = map[string]bool{
+	"fmt.Print": true, "fmt.Printf": true, "fmt.Println": true,
+	"(*encoding/csv.Writer).Write": true,
+}
+
+// safeWriters are the writers whose methods never return an error.
+var safeWriters = map[string]bool{
+	"bytes.Buffer": true, "strings.Builder": true,
+	"hash.Hash": true, "hash.Hash32": true, "hash.Hash64": true,
+}
+
+// isSafeWriter reports whether t, or what it points to, is a safe writer.
+func isSafeWriter(t types.Type) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	return t != nil && safeWriters[types.TypeString(t, nil)]
+}
+
+// exits and panics are the log functions that end the program or panic,
+// as package functions and as methods of a *log.Logger.
+var (
+	exits = map[string]bool{
+		"log.Fatal": true, "log.Fatalf": true, "log.Fatalln": true,
+		"(*log.Logger).Fatal": true, "(*log.Logger).Fatalf": true, "(*log.Logger).Fatalln": true,
+	}
+	panics = map[string]bool{
+		"log.Panic": true, "log.Panicf": true, "log.Panicln": true,
+		"(*log.Logger).Panic": true, "(*log.Logger).Panicf": true, "(*log.Logger).Panicln": true,
+	}
+)
+
+// stringMatchers are the strings functions used to match err.Error().
+var stringMatchers = map[string]bool{
+	"strings.Contains": true, "strings.HasPrefix": true, "strings.HasSuffix": true,
+	"strings.EqualFold": true, "strings.Index": true,
+}
+
+// isErrorType reports whether t is the error interface itself.
+func isErrorType(t types.Type) bool {
+	return t != nil && types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+// implementsError reports whether values of t are errors.
+func implementsError(t types.Type) bool {
+	return t != nil && types.Implements(t, errorType)
+}
+
+// calleeOf returns the function called by call, nil for builtins, function
+// values and conversions.
+func calleeOf(info *types.Info, call *ast.CallExpr) *types.Func {
+	var id *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		id = fun
+	case *ast.SelectorExpr:
+		id = fun.Sel
+	case *ast.IndexExpr: // generic instantiation f[T](…)
+		return calleeOf(info, &ast.CallExpr{Fun: fun.X})
+	}
+	if id == nil {
+		return nil
+	}
+	fn, _ := info.Uses[id].(*types.Func)
+	return fn
+}
+
+// calleeName returns a short name of the called function for messages.
+func calleeName(info *types.Info, call *ast.CallExpr) string {
+	if fn := calleeOf(info, call); fn != nil {
+		if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+			return types.TypeString(recv.Type(), func(p *types.Package) string { return p.Name() }) + "." + fn.Name()
+		}
+		if fn.Pkg() != nil {
+			return fn.Pkg().Name() + "." + fn.Name()
+		}
+	}
+	return types.ExprString(call.Fun)
+}
+
+// toStdio reports whether a fmt.Fprint* call writes to os.Stdout, os.Stderr
+// or a writer that never fails.
+func toStdio(info *types.Info, call *ast.CallExpr) bool {
+	if len(call.Args) == 0 {
+		return false
+	}
+	w := ast.Unparen(call.Args[0])
+	if sel, ok := w.(*ast.SelectorExpr); ok {
+		if v, ok := info.Uses[sel.Sel].(*types.Var); ok && v.Pkg() != nil && v.Pkg().Path() == "os" && (v.Name() == "Stdout" || v.Name() == "Stderr") {
+			return true
+		}
+	}
+	return isSafeWriter(info.TypeOf(w))
+}
+
+// errorResults returns the indexes of the results of call that are errors.
+func errorResults(info *types.Info, call *ast.CallExpr) []int {
+	var idx []int
+	switch t := info.TypeOf(call).(type) {
+	case *types.Tuple:
+		for i := 0; i < t.Len(); i++ {
+			if implementsError(t.At(i).Type()) {
+				idx = append(idx, i)
+			}
+		}
+	case nil:
+	default:
+		if implementsError(t) {
+			idx = append(idx, 0)
+		}
+	}
+	return idx
+}
+
+// isErrorString reports whether e is a call of the Error method of an error.
+func isErrorString(info *types.Info, e ast.Expr) bool {
+	call, ok := ast.Unparen(e).(*ast.CallExpr)
+	if !ok || len(call.Args) != 0 {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "Error" && implementsError(info.TypeOf(sel.X))
+}
+
+// badErrorString returns what is wrong with an error message: Go error
+// strings start lower case (acronyms and names aside) and have no trailing
+// punctuation, since they are usually wrapped into other messages.
+func badErrorString(msg string) string {
+	if msg == "" {
+		return ""
+	}
+	first, size := utf8.DecodeRuneInString(msg)
+	second, _ := utf8.DecodeRuneInString(msg[size:])
+	if unicode.IsUpper(first) && !unicode.IsUpper(second) && !unicode.IsDigit(second) {
+		return "is capitalized"
+	}
+	if last, _ := utf8.DecodeLastRuneInString(msg); strings.ContainsRune(".!?:\n", last) {
+		return "ends with punctuation"
+	}
+	return ""
+}
+
+// analyzeErrors implements Rule 18, error handling: unchecked errors,
+// comparing errors with ==, fmt.Errorf without %w, matching on err.Error(),
+// panic and log.Fatal outside package main, and the style of error strings.
+// Generated files are skipped, test files may panic.
+func analyzeErrors(p *project) []Finding {
+	var results []Finding
+	for _, tp := range p.typecheck() {
+		for _, f := range tp.files {
+			if !f.generated {
+				results = append(results, errorsInFile(p.fset, tp, f)...)
+			}
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return lessLocation(Location{File: results[i].File, Line: results[i].Line}, Location{File: results[j].File, Line: results[j].Line})
+	})
+	return results
+}
+
+func errorsInFile(fset *token.FileSet, tp *typedPackage, f projectFile) []Finding {
+	var results []Finding
+	report := func(pos token.Pos, severity Severity, rule, message, suggestion string) {
+		results = append(results, newFinding(fset, f.path, pos, severity, "Errors", rule, message, suggestion))
+	}
+	info := tp.info
+	unchecked := func(call *ast.CallExpr) bool {
+		fn := calleeOf(info, call)
+		if fn == nil {
+			return true
+		}
+		name := fn.FullName()
+		if uncheckedOK[name] {
+			return false
+		}
+		if sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr); ok && info.Selections[sel] != nil && isSafeWriter(info.TypeOf(sel.X)) {
+			return false
+		}
+		return !(strings.HasPrefix(name, "fmt.Fprint") && toStdio(info, call))
+	}
+
+	for _, decl := range f.node.Decls {
+		funcName := "" // enclosing declared function, for the panic rule
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			funcName = fn.Name.Name
+		}
+		ast.Inspect(decl, func(n ast.Node) bool {
+			switch n := n.(type) {
+			// Rule 18a: Errors are not discarded
+			case *ast.ExprStmt:
+				call, ok := ast.Unparen(n.X).(*ast.CallExpr)
+				if ok && len(errorResults(info, call)) > 0 && unchecked(call) {
+					report(call.Pos(), Medium, "unchecked-error", fmt.Sprintf("Error returned by %s is not checked", calleeName(info, call)),
+						"Handle the error, or return it to the caller: if err := "+types.ExprString(call.Fun)+"(…); err != nil { … }.")
+				}
+			case *ast.AssignStmt:
+				if len(n.Rhs) != 1 {
+					break
+				}
+				call, ok := ast.Unparen(n.Rhs[0]).(*ast.CallExpr)
+				if !ok {
+					break
+				}
+				for _, i := range errorResults(info, call) {
+					if i < len(n.Lhs) {
+						if id, ok := n.Lhs[i].(*ast.Ident); ok && id.Name == "_" && unchecked(call) {
+							report(id.Pos(), Medium, "unchecked-error", fmt.Sprintf("Error returned by %s is discarded with _", calleeName(info, call)),
+								"Handle the error; if it really cannot happen, say why in a comment.")
+						}
+					}
+				}
+
+			// Rule 18b: Errors are compared with errors.Is, wrapped errors never match ==
+			case *ast.BinaryExpr:
+				if n.Op != token.EQL && n.Op != token.NEQ {
+					break
+				}
+				if isErrorString(info, n.X) || isErrorString(info, n.Y) {
+					report(n.Pos(), Medium, "error-string-match", "Error matched by comparing err.Error() with a string",
+						"Compare with errors.Is against a sentinel error, or use errors.As for an error type; messages change.")
+					break
+				}
+				x, y := info.Types[n.X], info.Types[n.Y]
+				if (isErrorType(x.Type) && !y.IsNil() && isErrorType(y.Type)) || (isErrorType(y.Type) && !x.IsNil() && isErrorType(x.Type)) {
+					report(n.OpPos, Medium, "error-compare", fmt.Sprintf("Error compared with %s instead of errors.Is", n.Op),
+						fmt.Sprintf("Use errors.Is(%s, %s), == does not match errors wrapped with %%w.", types.ExprString(n.X), types.ExprString(n.Y)))
+				}
+			case *ast.SwitchStmt:
+				if n.Tag == nil {
+					break
+				}
+				if isErrorString(info, n.Tag) {
+					report(n.Pos(), Medium, "error-string-match", "Error matched by switching on err.Error()",
+						"Compare with errors.Is against sentinel errors, or use errors.As for error types; messages change.")
+				} else if isErrorType(info.TypeOf(n.Tag)) {
+					report(n.Pos(), Medium, "error-compare", "Error compared in a switch instead of errors.Is",
+						"Use switch { case errors.Is(err, ErrX): … }, a switch compares with == and misses wrapped errors.")
+				}
+
+			case *ast.CallExpr:
+				fn := calleeOf(info, n)
+				name := ""
+				if fn != nil {
+					name = fn.FullName()
+				}
+
+				// Rule 18d: err.Error() is not matched with strings functions
+				if stringMatchers[name] {
+					for _, arg := range n.Args {
+						if isErrorString(info, arg) {
+							report(n.Pos(), Medium, "error-string-match", fmt.Sprintf("Error matched with %s on err.Error()", calleeName(info, n)),
+								"Compare with errors.Is against a sentinel error, or use errors.As for an error type; messages change.")
+							break
+						}
+					}
+				}
+
+				// Rule 18c: fmt.Errorf wraps errors with %w
+				if name == "fmt.Errorf" && len(n.Args) > 1 {
+					format := info.Types[n.Args[0]].Value
+					hasError := false
+					for _, arg := range n.Args[1:] {
+						hasError = hasError || implementsError(info.TypeOf(arg))
+					}
+					if format != nil && format.Kind() == constant.String && hasError && !strings.Contains(constant.StringVal(format), "%w") {
+						report(n.Pos(), Low, "errorf-wrap", "fmt.Errorf formats an error without %w",
+							"Use %w for the error so callers can still inspect it with errors.Is and errors.As.")
+					}
+				}
+
+				// Rule 18f: Error strings are not capitalized and do not end with punctuation
+				if (name == "errors.New" || name == "fmt.Errorf") && len(n.Args) > 0 {
+					if msg := info.Types[n.Args[0]].Value; msg != nil && msg.Kind() == constant.String {
+						if problem := badErrorString(constant.StringVal(msg)); problem != "" {
+							report(n.Args[0].Pos(), Low, "error-string", fmt.Sprintf("Error string %q %s", constant.StringVal(msg), problem),
+								"Error strings start lower case and have no trailing punctuation, they are often wrapped: \"open config: file not found\".")
+						}
+					}
+				}
+
+				// Rule 18e: Libraries return errors instead of panicking or exiting
+				if tp.name == "main" || f.test || funcName == "init" || strings.HasPrefix(funcName, "Must") {
+					break
+				}
+				if id, ok := ast.Unparen(n.Fun).(*ast.Ident); ok && id.Name == "panic" {
+					if _, builtin := info.Uses[id].(*types.Builtin); builtin {
+						report(n.Pos(), Medium, "panic-in-library", fmt.Sprintf("panic in package %s", tp.name),
+							"Return an error and let the caller decide; panic only for programming errors, in a MustX function or in init.")
+					}
+				}
+				if exits[name] {
+					report(n.Pos(), Medium, "panic-in-library", fmt.Sprintf("%s in package %s exits the program", calleeName(info, n), tp.name),
+						"Return an error and let package main decide to exit, deferred calls do not run after log.Fatal.")
+				}
+				if panics[name] {
+					report(n.Pos(), Medium, "panic-in-library", fmt.Sprintf("%s in package %s panics", calleeName(info, n), tp.name),
+						"Return an error and let the caller decide; panic only for programming errors, in a MustX function or in init.")
+				}
+			}
+			return true
+		})
+	}
+	return results
+}
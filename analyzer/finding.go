@@ -1,5 +1,10 @@
 package analyzer
 
+import (
+	"go/token"
+	"time"
+)
+
 type Severity string
 
 const (
@@ -10,10 +15,48 @@ const (
 )
 
 type Finding struct {
-	File       string   `json:"file"`
-	Line       int      `json:"line"`
-	Message    string   `json:"message"`
-	Severity   Severity `json:"severity"`
-	Suggestion string   `json:"suggestion"`
-	Category   string   `json:"category"` // e.g., "Clean", "Performance", "Security"
+	File        string     `json:"file"`
+	Line        int        `json:"line"`
+	Column      int        `json:"column,omitempty"`
+	EndLine     int        `json:"end_line,omitempty"`
+	Message     string     `json:"message"`
+	Severity    Severity   `json:"severity"`
+	Suggestion  string     `json:"suggestion"`
+	Category    string     `json:"category"`              // e.g., "Clean", "Performance", "Security"
+	Rule        string     `json:"rule"`                  // e.g., "func-length", "G101", "SA4006"
+	Fingerprint string     `json:"fingerprint,omitempty"` // stable across runs, see assignFingerprints
+	Owners      []string   `json:"owners,omitempty"`      // from CODEOWNERS, see package owners
+	Blame       *Blame     `json:"blame,omitempty"`       // set with --blame, see vcs.AnnotateBlame
+	Coverage    *float64   `json:"coverage,omitempty"`    // statement coverage of the enclosing function in percent, set with --coverprofile
+	Related     []Location `json:"related,omitempty"`     // other places involved, e.g. the other copies of duplicated code
+}
+
+// newFinding builds the finding of a rule reported at pos in file.
+func newFinding(fset *token.FileSet, file string, pos token.Pos, severity Severity, category, rule, message, suggestion string) Finding {
+	p := fset.Position(pos)
+	return Finding{
+		File:       file,
+		Line:       p.Line,
+		Column:     p.Column,
+		Message:    message,
+		Severity:   severity,
+		Suggestion: suggestion,
+		Category:   category,
+		Rule:       rule,
+	}
+}
+
+// Location is a range of lines in a file.
+type Location struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	EndLine int    `json:"end_line"`
+}
+
+// Blame records the last commit that touched the line of a finding.
+type Blame struct {
+	Commit string    `json:"commit"`
+	Author string    `json:"author"`
+	Email  string    `json:"email,omitempty"`
+	Date   time.Time `json:"date"`
 }
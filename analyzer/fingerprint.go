@@ -0,0 +1,81 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// assignFingerprints gives every finding an identifier that survives code
+// moving around: it is built from the rule, the file relative to root and
+// the trimmed text of the offending line rather than the line number, so
+// two checkouts of the same repository in different directories agree.
+// Identical lines reported by the same rule in the same file are told apart
+// by their order.
+func assignFingerprints(findings []Finding, root string) {
+	sources := map[string][]string{}
+	seen := map[string]int{}
+	for i := range findings {
+		f := &findings[i]
+		file := normalizePath(f.File, root)
+		lines, ok := sources[f.File]
+		if !ok {
+			if data, err := os.ReadFile(f.File); err == nil {
+				lines = strings.Split(string(data), "\n")
+			}
+			sources[f.File] = lines
+		}
+		text := f.Message
+		if f.Line >= 1 && f.Line <= len(lines) {
+			text = strings.TrimSpace(lines[f.Line-1])
+		}
+		key := f.Rule + "\x00" + file + "\x00" + text
+		seen[key]++
+		sum := sha256.Sum256([]byte(key + "\x00" + strconv.Itoa(seen[key])))
+		f.Fingerprint = hex.EncodeToString(sum[:8])
+	}
+}
+
+// normalizePath makes paths reported by different tools and from different
+// checkouts comparable: gosec reports absolute paths while the built-in
+// rules use the scanned path, so both are made relative to root. Files
+// outside root, or every file when root is "", keep their cleaned path.
+func normalizePath(file, root string) string {
+	if root != "" {
+		if rel, ok := relPath(root, file); ok {
+			file = rel
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(file))
+}
+
+// relPath returns file relative to root if file is inside root, resolving
+// symbolic links when the plain paths do not match (git reports the
+// top-level directory with links resolved).
+func relPath(root, file string) (string, bool) {
+	absRoot, err1 := filepath.Abs(root)
+	absFile, err2 := filepath.Abs(file)
+	if err1 != nil || err2 != nil {
+		return "", false
+	}
+	if rel, ok := within(absRoot, absFile); ok {
+		return rel, true
+	}
+	realRoot, err1 := filepath.EvalSymlinks(absRoot)
+	realFile, err2 := filepath.EvalSymlinks(absFile)
+	if err1 != nil || err2 != nil {
+		return "", false
+	}
+	return within(realRoot, realFile)
+}
+
+func within(root, file string) (string, bool) {
+	rel, err := filepath.Rel(root, file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
@@ -0,0 +1,57 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestAssignFingerprints scans two checkouts of the same repository in
+// different directories, one relative to the working directory and one by
+// absolute path as gosec reports it, after the finding moved down.
+func TestAssignFingerprints(t *testing.T) {
+	const before = "package p\n\nfunc f() {\n\tpanic(\"x\")\n}\n"
+	const after = "package p\n\n// f panics.\n\nfunc f() {\n\tpanic(\"x\")\n}\n"
+	base := t.TempDir()
+	oldRoot := filepath.Join(base, "release")
+	newRoot := filepath.Join(base, "worktrees", "v2")
+	for root, src := range map[string]string{oldRoot: before, newRoot: after} {
+		if err := os.MkdirAll(filepath.Join(root, "pkg"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, "pkg", "p.go"), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(oldRoot); err != nil {
+		t.Fatal(err)
+	}
+	old := []Finding{{File: "pkg/p.go", Line: 4, Rule: "panic", Message: "panic"}}
+	assignFingerprints(old, ".")
+
+	if err := os.Chdir(base); err != nil {
+		t.Fatal(err)
+	}
+	moved := []Finding{{File: filepath.Join(newRoot, "pkg", "p.go"), Line: 6, Rule: "panic", Message: "panic"}}
+	assignFingerprints(moved, newRoot)
+
+	if old[0].Fingerprint == "" || old[0].Fingerprint != moved[0].Fingerprint {
+		t.Errorf("fingerprints differ across checkouts: %q and %q", old[0].Fingerprint, moved[0].Fingerprint)
+	}
+
+	// another file with the same line is another finding
+	other := []Finding{{File: filepath.Join(newRoot, "p.go"), Line: 6, Rule: "panic", Message: "panic"}}
+	if err := os.WriteFile(other[0].File, []byte(after), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	assignFingerprints(other, newRoot)
+	if other[0].Fingerprint == moved[0].Fingerprint {
+		t.Errorf("fingerprint of %s equals that of pkg/p.go", other[0].File)
+	}
+}
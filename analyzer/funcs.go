@@ -0,0 +1,42 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"github.com/gotech-hub/gocheck/metrics"
+)
+
+// funcUnit is a function analyzed on its own: a declared function or method,
+// or a function literal.
+type funcUnit struct {
+	Name string // "Name", "Type.Method" or "func literal in Name"
+	Pos  token.Pos
+	Decl *ast.FuncDecl // nil for function literals
+	Type *ast.FuncType
+	Body *ast.BlockStmt
+}
+
+// funcUnits returns every function declaration and function literal of the
+// file that has a body, in source order.
+func funcUnits(fset *token.FileSet, node *ast.File) []funcUnit {
+	var units []funcUnit
+	for _, decl := range node.Decls {
+		outer := "package level"
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			outer = metrics.FuncName(fn)
+			if fn.Body != nil {
+				units = append(units, funcUnit{Name: outer, Pos: fn.Pos(), Decl: fn, Type: fn.Type, Body: fn.Body})
+			}
+		}
+		ast.Inspect(decl, func(n ast.Node) bool {
+			if lit, ok := n.(*ast.FuncLit); ok {
+				name := fmt.Sprintf("func literal in %s (line %d)", outer, fset.Position(lit.Pos()).Line)
+				units = append(units, funcUnit{Name: name, Pos: lit.Pos(), Type: lit.Type, Body: lit.Body})
+			}
+			return true
+		})
+	}
+	return units
+}
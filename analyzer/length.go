@@ -0,0 +1,93 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"github.com/gotech-hub/gocheck/metrics"
+)
+
+// funcLength measures fn with metric, one of the FuncLength constants, and
+// returns the length with the unit used in messages. codeLines are the lines
+// of the file holding code, only needed for FuncLengthLogical. Function
+// literals inside fn are measured as functions of their own, in every metric
+// only the lines or the statement that hold them count for fn.
+func funcLength(fset *token.FileSet, fn funcUnit, codeLines map[int]bool, metric string) (int, string) {
+	start := fset.Position(fn.Pos).Line
+	end := fset.Position(fn.Body.Rbrace).Line
+	if metric == FuncLengthStatements {
+		count := 0
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch n.(type) {
+			case *ast.FuncLit:
+				return false // measured as a function of its own
+			case *ast.BlockStmt:
+			case ast.Stmt:
+				count++
+			}
+			return true
+		})
+		return count, "statements"
+	}
+	nested := literalLines(fset, fn.Body)
+	count := 0
+	for line := start; line <= end; line++ {
+		if nested[line] {
+			continue
+		}
+		if metric == FuncLengthPhysical || codeLines[line] {
+			count++
+		}
+	}
+	if metric == FuncLengthPhysical {
+		return count, "physical lines"
+	}
+	return count, "lines of code"
+}
+
+// literalLines returns the lines between the braces of the function literals
+// in body; the lines of the braces hold code of the enclosing function too.
+func literalLines(fset *token.FileSet, body *ast.BlockStmt) map[int]bool {
+	lines := map[int]bool{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		lit, ok := n.(*ast.FuncLit)
+		if !ok {
+			return true
+		}
+		from, to := fset.Position(lit.Body.Lbrace).Line, fset.Position(lit.Body.Rbrace).Line
+		for line := from + 1; line < to; line++ {
+			lines[line] = true
+		}
+		return false
+	})
+	return lines
+}
+
+// analyzeFuncLength implements Rule 1 for every function, method and
+// function literal of the file.
+func analyzeFuncLength(file string, fset *token.FileSet, node *ast.File, src []byte, cfg Config) []Finding {
+	var results []Finding
+	var codeLines map[int]bool
+	if cfg.FuncLength == FuncLengthLogical {
+		codeLines = metrics.CodeLines(fset.File(node.Pos()), src)
+	}
+	for _, fn := range funcUnits(fset, node) {
+		length, unit := funcLength(fset, fn, codeLines, cfg.FuncLength)
+		if length <= cfg.MaxFuncLength {
+			continue
+		}
+		pos := fset.Position(fn.Pos)
+		results = append(results, Finding{
+			File:       file,
+			Line:       pos.Line,
+			Column:     pos.Column,
+			Message:    fmt.Sprintf("Function %s is too long (%d %s, max %d)", fn.Name, length, unit, cfg.MaxFuncLength),
+			Severity:   Medium,
+			Suggestion: "Split the function into smaller functions for better readability and testability.",
+			Category:   "Clean",
+			Rule:       "func-length",
+		})
+	}
+	return results
+}
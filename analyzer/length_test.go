@@ -0,0 +1,50 @@
+package analyzer
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/gotech-hub/gocheck/metrics"
+)
+
+const lengthSrc = `package p
+
+func outer() {
+	a := 1
+	handler := func() {
+		b := 2
+		// comment
+		_ = b
+	}
+	handler()
+	_ = a
+}
+`
+
+func TestFuncLength(t *testing.T) {
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, "p.go", lengthSrc, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	codeLines := metrics.CodeLines(fset.File(node.Pos()), []byte(lengthSrc))
+	want := map[string]map[string]int{
+		"outer":                          {FuncLengthPhysical: 7, FuncLengthLogical: 7, FuncLengthStatements: 4},
+		"func literal in outer (line 5)": {FuncLengthPhysical: 5, FuncLengthLogical: 4, FuncLengthStatements: 2},
+	}
+	fns := funcUnits(fset, node)
+	if len(fns) != len(want) {
+		t.Fatalf("got %d functions, want %d", len(fns), len(want))
+	}
+	for _, fn := range fns {
+		if want[fn.Name] == nil {
+			t.Errorf("unexpected function %q", fn.Name)
+		}
+		for metric, n := range want[fn.Name] {
+			if got, _ := funcLength(fset, fn, codeLines, metric); got != n {
+				t.Errorf("%s %s length = %d, want %d", fn.Name, metric, got, n)
+			}
+		}
+	}
+}
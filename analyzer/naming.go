@@ -0,0 +1,346 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gotech-hub/gocheck/metrics"
+)
+
+// maxReceiverName is the longest receiver name considered short. Go code
+// uses one or two letter abbreviations of the type.
+const maxReceiverName = 3
+
+// initialisms are the words Go writes in a single case, e.g. ID and URL
+// rather than Id and Url. The list is the one golint used.
+var initialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true,
+	"EOF": true, "GUID": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true,
+	"IP": true, "JSON": true, "LHS": true, "QPS": true, "RAM": true, "RHS": true,
+	"RPC": true, "SLA": true, "SMTP": true, "SQL": true, "SSH": true, "TCP": true,
+	"TLS": true, "TTL": true, "UDP": true, "UI": true, "UID": true, "UUID": true,
+	"URI": true, "URL": true, "UTF8": true, "VM": true, "XML": true, "XMPP": true,
+	"XSRF": true, "XSS": true,
+}
+
+// splitWords splits a MixedCaps name into words: "HTTPServerId" gives
+// "HTTP", "Server", "Id".
+func splitWords(name string) []string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		lowerToUpper := (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])) && unicode.IsUpper(runes[i])
+		endOfAcronym := i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsUpper(runes[i]) && unicode.IsLower(runes[i+1])
+		if lowerToUpper || endOfAcronym {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(words, string(runes[start:]))
+}
+
+// fixInitialisms returns name with every initialism in a single case. A
+// lower-case initialism starting an unexported name, as in urlPath, is fine.
+func fixInitialisms(name string) string {
+	words := splitWords(name)
+	for i, w := range words {
+		upper := strings.ToUpper(w)
+		if !initialisms[upper] || w == upper || (i == 0 && w == strings.ToLower(w)) {
+			continue
+		}
+		words[i] = upper
+	}
+	return strings.Join(words, "")
+}
+
+// toMixedCaps turns snake_case and SCREAMING_CASE into MixedCaps, keeping
+// the case of the first letter so exported names stay exported.
+func toMixedCaps(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool { return r == '_' })
+	for i, part := range parts {
+		if part == strings.ToUpper(part) {
+			part = part[:1] + strings.ToLower(part[1:])
+		}
+		if i == 0 && unicode.IsLower(rune(parts[0][0])) {
+			part = strings.ToLower(part[:1]) + part[1:]
+		} else {
+			part = strings.ToUpper(part[:1]) + part[1:]
+		}
+		parts[i] = part
+	}
+	return fixInitialisms(strings.Join(parts, ""))
+}
+
+// isTestFunc reports whether name follows the go test naming, where an
+// underscore is allowed: TestX_y, ExampleT_Method, BenchmarkX_y, FuzzX_y.
+func isTestFunc(name string) bool {
+	for _, prefix := range []string{"Test", "Example", "Benchmark", "Fuzz"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// receiverType returns the name of the type a method is declared on.
+func receiverType(fn *ast.FuncDecl) string {
+	name := metrics.FuncName(fn)
+	if i := strings.Index(name, "."); i >= 0 {
+		return name[:i]
+	}
+	return ""
+}
+
+// returnsError reports whether expr builds a new error value.
+func returnsError(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && (pkg.Name == "errors" && sel.Sel.Name == "New" || pkg.Name == "fmt" && sel.Sel.Name == "Errorf")
+}
+
+// analyzeNaming implements Rule 7, the Go naming conventions: MixedCaps,
+// initialisms, receiver names, package stutter, getters and error names.
+// Generated files are skipped.
+func analyzeNaming(file string, fset *token.FileSet, node *ast.File) []Finding {
+	if ast.IsGenerated(node) {
+		return nil
+	}
+	var results []Finding
+	report := func(pos token.Pos, rule, message, suggestion string) {
+		results = append(results, newFinding(fset, file, pos, Low, "Clean", rule, message, suggestion))
+	}
+	testFile := strings.HasSuffix(file, "_test.go")
+
+	// Rule 7a/7b: MixedCaps and initialisms for every declared name
+	check := func(id *ast.Ident) {
+		if id == nil || id.Name == "_" {
+			return
+		}
+		// a redeclaration in "a, err := …" is not a new name
+		if id.Obj != nil && id.Obj.Pos() != id.Pos() {
+			return
+		}
+		if strings.Contains(strings.Trim(id.Name, "_"), "_") || strings.HasPrefix(id.Name, "_") {
+			report(id.Pos(), "mixed-caps", fmt.Sprintf("Name '%s' uses underscores", id.Name),
+				fmt.Sprintf("Go names use MixedCaps, rename it to %s.", toMixedCaps(id.Name)))
+			return
+		}
+		if fixed := fixInitialisms(id.Name); fixed != id.Name {
+			report(id.Pos(), "initialism", fmt.Sprintf("Name '%s' should be '%s'", id.Name, fixed),
+				"Initialisms such as ID, URL and HTTP keep a single case in Go names.")
+		}
+	}
+	checkFields := func(fields *ast.FieldList) {
+		if fields == nil {
+			return
+		}
+		for _, f := range fields.List {
+			for _, id := range f.Names {
+				check(id)
+			}
+		}
+	}
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			if !(testFile && isTestFunc(n.Name.Name)) {
+				check(n.Name)
+			}
+			checkFields(n.Recv)
+		case *ast.FuncType:
+			checkFields(n.Params)
+			checkFields(n.Results)
+		case *ast.TypeSpec:
+			check(n.Name)
+		case *ast.ValueSpec:
+			for _, id := range n.Names {
+				check(id)
+			}
+		case *ast.StructType:
+			checkFields(n.Fields)
+		case *ast.InterfaceType:
+			checkFields(n.Methods)
+		case *ast.AssignStmt:
+			if n.Tok == token.DEFINE {
+				for _, lhs := range n.Lhs {
+					if id, ok := lhs.(*ast.Ident); ok {
+						check(id)
+					}
+				}
+			}
+		case *ast.RangeStmt:
+			if n.Tok == token.DEFINE {
+				for _, e := range []ast.Expr{n.Key, n.Value} {
+					if id, ok := e.(*ast.Ident); ok {
+						check(id)
+					}
+				}
+			}
+		}
+		return true
+	})
+
+	pkg := node.Name.Name
+	errorTypes := map[string]bool{} // types with an Error() string method
+	for _, decl := range node.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		if fn.Recv != nil && fn.Name.Name == "Error" && fn.Type.Params.NumFields() == 0 && fn.Type.Results.NumFields() == 1 {
+			if res, ok := fn.Type.Results.List[0].Type.(*ast.Ident); ok && res.Name == "string" {
+				errorTypes[receiverType(fn)] = true
+			}
+		}
+
+		// Rule 7c: Receiver names are short and never this/self
+		if fn.Recv != nil && len(fn.Recv.List) > 0 && len(fn.Recv.List[0].Names) > 0 {
+			recv := fn.Recv.List[0].Names[0]
+			if recv.Name == "this" || recv.Name == "self" || len(recv.Name) > maxReceiverName {
+				suggestion := "Use one or two letters of the type name, and the same name in every method."
+				if typ := receiverType(fn); typ != "" {
+					suggestion = fmt.Sprintf("Use one or two letters such as '%s', and the same name in every method of %s.", strings.ToLower(typ[:1]), typ)
+				}
+				report(recv.Pos(), "receiver-name", fmt.Sprintf("Receiver name '%s' of %s should be a short abbreviation of the type", recv.Name, metrics.FuncName(fn)), suggestion)
+			}
+		}
+
+		// Rule 7e: Getters are named X, not GetX
+		name := fn.Name.Name
+		if fn.Recv != nil && len(name) > 3 && strings.HasPrefix(name, "Get") && unicode.IsUpper(rune(name[3])) &&
+			fn.Type.Params.NumFields() == 0 && fn.Type.Results.NumFields() > 0 {
+			report(fn.Name.Pos(), "getter-name", fmt.Sprintf("Getter %s should be named %s", metrics.FuncName(fn), name[3:]),
+				fmt.Sprintf("Go getters drop the Get prefix: %s(); a setter would be Set%s.", name[3:], name[3:]))
+		}
+	}
+
+	for _, decl := range node.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			// Rule 7d: Package stutter, user.UserService reads "user.User…"
+			if d.Recv == nil {
+				checkStutter(pkg, d.Name, report)
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					checkStutter(pkg, s.Name, report)
+					// Rule 7f: Error types are named XError
+					if errorTypes[s.Name.Name] && !strings.HasSuffix(s.Name.Name, "Error") && s.Name.Name != "Error" {
+						report(s.Name.Pos(), "error-type-name", fmt.Sprintf("Error type %s should be named %sError", s.Name.Name, strings.TrimSuffix(s.Name.Name, "Err")),
+							"Types implementing error are named XError, e.g. PathError.")
+					}
+				case *ast.ValueSpec:
+					if d.Tok != token.VAR {
+						continue
+					}
+					for i, id := range s.Names {
+						// Rule 7f: Error variables are named ErrX / errX
+						if i < len(s.Values) && returnsError(s.Values[i]) && !strings.HasPrefix(id.Name, "Err") && !strings.HasPrefix(id.Name, "err") && id.Name != "_" {
+							want := "err" + strings.ToUpper(id.Name[:1]) + id.Name[1:]
+							if ast.IsExported(id.Name) {
+								want = "Err" + id.Name
+							}
+							report(id.Pos(), "error-var-name", fmt.Sprintf("Error variable %s should be named %s", id.Name, strings.TrimSuffix(want, "Error")),
+								"Sentinel errors are named ErrX when exported and errX otherwise.")
+						}
+					}
+				}
+			}
+		}
+	}
+	return results
+}
+
+// checkStutter reports exported names repeating the package name, which
+// callers already write: user.UserService should be user.Service.
+func checkStutter(pkg string, id *ast.Ident, report func(token.Pos, string, string, string)) {
+	if pkg == "main" || !ast.IsExported(id.Name) || len(id.Name) <= len(pkg) {
+		return
+	}
+	if !strings.EqualFold(id.Name[:len(pkg)], pkg) {
+		return
+	}
+	rest := id.Name[len(pkg):]
+	if !unicode.IsUpper(rune(rest[0])) {
+		return
+	}
+	report(id.Pos(), "package-stutter", fmt.Sprintf("%s.%s stutters", pkg, id.Name),
+		fmt.Sprintf("Callers already write the package name, consider %s.%s.", pkg, rest))
+}
+
+// analyzeReceiverConsistency reports methods whose receiver name differs
+// from the one used by most methods of the same type. Methods of a type can
+// be spread over the files of its package, so this runs per directory.
+func analyzeReceiverConsistency(p *project) []Finding {
+	type method struct {
+		file string
+		pos  token.Pos
+		name string // receiver name
+		decl string // Type.Method
+	}
+	byType := map[string][]method{} // key: directory + type name
+	for _, f := range p.files {
+		if f.generated {
+			continue
+		}
+		for _, decl := range f.node.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || len(fn.Recv.List) == 0 || len(fn.Recv.List[0].Names) == 0 {
+				continue
+			}
+			recv := fn.Recv.List[0].Names[0]
+			if recv.Name == "_" || receiverType(fn) == "" {
+				continue
+			}
+			key := f.dir + "\x00" + receiverType(fn)
+			byType[key] = append(byType[key], method{file: f.path, pos: recv.Pos(), name: recv.Name, decl: metrics.FuncName(fn)})
+		}
+	}
+
+	var keys []string
+	for key := range byType {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var results []Finding
+	for _, key := range keys {
+		methods := byType[key]
+		counts := map[string]int{}
+		for _, m := range methods {
+			counts[m.name]++
+		}
+		if len(counts) < 2 {
+			continue
+		}
+		// the most used name wins, ties go to the first method
+		common := methods[0].name
+		for _, m := range methods {
+			if counts[m.name] > counts[common] {
+				common = m.name
+			}
+		}
+		for _, m := range methods {
+			if m.name == common {
+				continue
+			}
+			results = append(results, newFinding(p.fset, m.file, m.pos, Low, "Clean", "receiver-consistency",
+				fmt.Sprintf("Receiver of %s is named '%s' but other methods use '%s'", m.decl, m.name, common),
+				fmt.Sprintf("Use the same receiver name in every method of the type: '%s'.", common)))
+		}
+	}
+	return results
+}
@@ -0,0 +1,25 @@
+package analyzer
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func TestAnalyzeNamingParenReceiver(t *testing.T) {
+	src := "package x\n\ntype T int\n\nfunc (self (T)) Name() string { return \"\" }\n"
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, "x.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, f := range analyzeNaming("x.go", fset, node) {
+		if f.Rule == "receiver-name" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("receiver-name not reported for self on a parenthesized receiver")
+	}
+}
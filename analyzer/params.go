@@ -0,0 +1,113 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// param is one parameter or result of a function. Unnamed ones are named
+// after their type.
+type param struct {
+	Name string
+	Type ast.Expr
+	Pos  token.Pos
+}
+
+// flattenFields expands a parameter or result list to one entry per name,
+// so "a, b int" gives two entries.
+func flattenFields(fields *ast.FieldList) []param {
+	if fields == nil {
+		return nil
+	}
+	var params []param
+	for _, field := range fields.List {
+		if len(field.Names) == 0 {
+			params = append(params, param{Name: types.ExprString(field.Type), Type: field.Type, Pos: field.Pos()})
+			continue
+		}
+		for _, name := range field.Names {
+			params = append(params, param{Name: name.Name, Type: field.Type, Pos: name.Pos()})
+		}
+	}
+	return params
+}
+
+func paramNames(params []param) string {
+	names := make([]string, len(params))
+	for i, p := range params {
+		names[i] = p.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func isBool(t ast.Expr) bool {
+	ident, ok := t.(*ast.Ident)
+	return ok && ident.Name == "bool"
+}
+
+// analyzeParams implements Rule 2 and its companions for one function:
+// too many parameters, too many results and adjacent bool parameters.
+func analyzeParams(file string, fset *token.FileSet, fn *ast.FuncDecl) []Finding {
+	var results []Finding
+	pos := fset.Position(fn.Pos())
+	name := fn.Name.Name
+	// the suggested options type is exported only if the function is, so
+	// newServer gets newServerOptions and does not widen the package API
+	options := name + "Options"
+
+	// Rule 2: Function has too many parameters, counted by name
+	params := flattenFields(fn.Type.Params)
+	if len(params) > maxFuncParams {
+		results = append(results, Finding{
+			File:       file,
+			Line:       pos.Line,
+			Column:     pos.Column,
+			Message:    fmt.Sprintf("Function %s has too many parameters (%d, max %d)", name, len(params), maxFuncParams),
+			Severity:   Medium,
+			Suggestion: fmt.Sprintf("Group %s into a struct such as %s, or use functional options for the optional ones.", paramNames(params), options),
+			Category:   "Clean",
+			Rule:       "param-count",
+		})
+	}
+
+	// Rule 2b: Function returns too many results
+	if res := flattenFields(fn.Type.Results); len(res) > maxFuncResults {
+		results = append(results, Finding{
+			File:       file,
+			Line:       pos.Line,
+			Column:     pos.Column,
+			Message:    fmt.Sprintf("Function %s returns too many results (%d, max %d)", name, len(res), maxFuncResults),
+			Severity:   Low,
+			Suggestion: fmt.Sprintf("Return a struct holding %s instead of separate values.", paramNames(res)),
+			Category:   "Clean",
+			Rule:       "result-count",
+		})
+	}
+
+	// Rule 2c: Adjacent bool parameters ("boolean trap"), calls like f(true, false) are unreadable
+	for i := 0; i < len(params); {
+		j := i
+		for j < len(params) && isBool(params[j].Type) {
+			j++
+		}
+		if j-i >= 2 {
+			run := params[i:j]
+			boolPos := fset.Position(run[0].Pos)
+			results = append(results, Finding{
+				File:       file,
+				Line:       boolPos.Line,
+				Column:     boolPos.Column,
+				Message:    fmt.Sprintf("Function %s takes %d adjacent bool parameters (%s)", name, len(run), paramNames(run)),
+				Severity:   Low,
+				Suggestion: fmt.Sprintf("Callers end up writing %s(true, false, …). Replace %s with fields of an options struct such as %s, or with named constants of a flag type.", name, paramNames(run), options),
+				Category:   "Clean",
+				Rule:       "bool-params",
+			})
+		}
+		i = max(j, i+1)
+	}
+	return results
+}
@@ -0,0 +1,32 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeParamsOptionsName(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"func NewServer(tls, http2 bool) {}", "NewServerOptions"},
+		{"func newServer(tls, http2 bool) {}", "newServerOptions"},
+		{"func émettre(sync, retry bool) {}", "émettreOptions"},
+		{"func Émettre(sync, retry bool) {}", "ÉmettreOptions"},
+	}
+	for _, tt := range tests {
+		fset := token.NewFileSet()
+		node, err := parser.ParseFile(fset, "x.go", "package x\n"+tt.src, 0)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.src, err)
+		}
+		findings := analyzeParams("x.go", fset, node.Decls[0].(*ast.FuncDecl))
+		if len(findings) != 1 || !strings.Contains(findings[0].Suggestion, " "+tt.want+",") {
+			t.Errorf("%s: findings %+v, want a bool-params suggestion naming %s", tt.src, findings, tt.want)
+		}
+	}
+}
@@ -26,10 +26,12 @@ func analyzePerformance(file string) []Finding {
 				results = append(results, Finding{
 					File:       file,
 					Line:       pos.Line,
+					Column:     pos.Column,
 					Message:    "For-loop detected — review for potential performance impact",
 					Severity:   Low,
 					Suggestion: "Check the loop's exit condition or review for nested loops that may impact performance.",
 					Category:   "Performance",
+					Rule:       "for-loop",
 				})
 
 				// Rule: defer in loop
@@ -39,10 +41,12 @@ func analyzePerformance(file string) []Finding {
 						results = append(results, Finding{
 							File:       file,
 							Line:       deferPos.Line,
+							Column:     deferPos.Column,
 							Message:    "Use of 'defer' inside a loop can cause performance issues.",
 							Severity:   Medium,
 							Suggestion: "Move 'defer' outside the loop if possible, or consider alternative resource management.",
 							Category:   "Performance",
+							Rule:       "defer-in-loop",
 						})
 					}
 					return true
@@ -55,10 +59,12 @@ func analyzePerformance(file string) []Finding {
 						results = append(results, Finding{
 							File:       file,
 							Line:       goPos.Line,
+							Column:     goPos.Column,
 							Message:    "Launching goroutines inside a loop can cause race conditions or high overhead.",
 							Severity:   Medium,
 							Suggestion: "Consider batching data or using a worker pool instead of launching goroutines inside a loop.",
 							Category:   "Performance",
+							Rule:       "go-in-loop",
 						})
 					}
 					return true
@@ -73,10 +79,12 @@ func analyzePerformance(file string) []Finding {
 								results = append(results, Finding{
 									File:       file,
 									Line:       assignPos.Line,
+									Column:     assignPos.Column,
 									Message:    "String concatenation (+=) inside a loop can be slow.",
 									Severity:   Low,
 									Suggestion: "Use strings.Builder for string concatenation inside loops.",
 									Category:   "Performance",
+									Rule:       "string-concat-loop",
 								})
 							}
 						}
@@ -106,8 +114,9 @@ func runExternalPerformanceAnalyzer(file string) []Finding {
 		Code     string `json:"code"`
 		Severity string `json:"severity"`
 		Location struct {
-			File string `json:"file"`
-			Line int    `json:"line"`
+			File   string `json:"file"`
+			Line   int    `json:"line"`
+			Column int    `json:"column"`
 		} `json:"location"`
 		End struct {
 			Line int `json:"line"`
@@ -130,10 +139,13 @@ func runExternalPerformanceAnalyzer(file string) []Finding {
 		findings = append(findings, Finding{
 			File:       issue.Location.File,
 			Line:       issue.Location.Line,
+			Column:     issue.Location.Column,
+			EndLine:    issue.End.Line,
 			Message:    fmt.Sprintf("[staticcheck][%s] %s", issue.Code, issue.Message),
 			Severity:   sev,
 			Suggestion: "Check staticcheck documentation for details.",
 			Category:   "Performance",
+			Rule:       issue.Code,
 		})
 	}
 	return findings
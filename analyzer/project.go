@@ -0,0 +1,67 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// project holds every file of a scan parsed once, for the rules that look
+// across files or need type information: receiver consistency, package
+// comments, clones, dead code, unused variables and error handling.
+type project struct {
+	fset  *token.FileSet
+	files []projectFile   // in scan order
+	typed []*typedPackage // set by typecheck
+}
+
+// projectFile is one parsed file of a project.
+type projectFile struct {
+	path      string
+	dir       string
+	node      *ast.File
+	src       []byte
+	test      bool // a _test.go file
+	generated bool
+}
+
+// loadProject parses files with their comments. Files that cannot be read
+// or parsed are left out, the per-file rules already skip them.
+func loadProject(files []string) *project {
+	p := &project{fset: token.NewFileSet()}
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		node, err := parser.ParseFile(p.fset, file, src, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		p.files = append(p.files, projectFile{
+			path:      file,
+			dir:       filepath.Dir(file),
+			node:      node,
+			src:       src,
+			test:      strings.HasSuffix(file, "_test.go"),
+			generated: ast.IsGenerated(node),
+		})
+	}
+	return p
+}
+
+// analyzeProject runs the cross-file rules.
+func analyzeProject(files []string) []Finding {
+	p := loadProject(files)
+	var results []Finding
+	results = append(results, analyzeReceiverConsistency(p)...)
+	results = append(results, analyzeDocPackages(p)...)
+	results = append(results, analyzeClones(p)...)
+	results = append(results, analyzeDeadCode(p)...)
+	results = append(results, analyzeUnused(p)...)
+	results = append(results, analyzeErrors(p)...)
+	return results
+}
@@ -7,6 +7,7 @@ import (
 	"go/parser"
 	"go/token"
 	"os/exec"
+	"strconv"
 	"strings"
 )
 
@@ -30,10 +31,12 @@ func analyzeSecurity(file string) []Finding {
 						results = append(results, Finding{
 							File:       file,
 							Line:       pos.Line,
+							Column:     pos.Column,
 							Message:    fmt.Sprintf("Hardcoded credential: %s", bl.Value),
 							Severity:   High,
 							Suggestion: "Do not hardcode passwords/API keys. Use environment variables or configuration files instead.",
 							Category:   "Security",
+							Rule:       "hardcoded-credential",
 						})
 					}
 				}
@@ -48,10 +51,12 @@ func analyzeSecurity(file string) []Finding {
 					results = append(results, Finding{
 						File:       file,
 						Line:       pos.Line,
+						Column:     pos.Column,
 						Message:    "Use of exec.Command detected (possible command injection)",
 						Severity:   High,
 						Suggestion: "Avoid passing unchecked input to exec.Command. Use input validation and sanitization.",
 						Category:   "Security",
+						Rule:       "exec-command",
 					})
 				}
 			}
@@ -69,10 +74,12 @@ func analyzeSecurity(file string) []Finding {
 								results = append(results, Finding{
 									File:       file,
 									Line:       pos.Line,
+									Column:     pos.Column,
 									Message:    "Use of http.ListenAndServe on insecure port (:80 or :8080)",
 									Severity:   Medium,
 									Suggestion: "Use HTTPS (443) instead of HTTP (80/8080) for production services.",
 									Category:   "Security",
+									Rule:       "insecure-port",
 								})
 							}
 						}
@@ -90,10 +97,12 @@ func analyzeSecurity(file string) []Finding {
 						results = append(results, Finding{
 							File:       file,
 							Line:       pos.Line,
+							Column:     pos.Column,
 							Message:    fmt.Sprintf("Use of insecure hash function: %s.New", pkg.Name),
 							Severity:   High,
 							Suggestion: "Do not use md5 or sha1 for security purposes. Use sha256 or stronger algorithms instead.",
 							Category:   "Security",
+							Rule:       "weak-hash",
 						})
 					}
 				}
@@ -112,10 +121,12 @@ func analyzeSecurity(file string) []Finding {
 									results = append(results, Finding{
 										File:       file,
 										Line:       pos.Line,
+										Column:     pos.Column,
 										Message:    "tls.Config with InsecureSkipVerify: true detected (insecure TLS)",
 										Severity:   Critical,
 										Suggestion: "Never set InsecureSkipVerify to true in production. This disables certificate validation and is highly insecure.",
 										Category:   "Security",
+										Rule:       "insecure-tls",
 									})
 								}
 							}
@@ -155,6 +166,7 @@ func runGosec(file string) []Finding {
 			File    string `json:"file"`
 			Code    string `json:"code"`
 			Line    int    `json:"line"`
+			Column  string `json:"column"`
 		} `json:"issues"`
 	}
 	var res gosecResult
@@ -175,13 +187,16 @@ func runGosec(file string) []Finding {
 		case "critical":
 			sev = Critical
 		}
+		column, _ := strconv.Atoi(issue.Column)
 		findings = append(findings, Finding{
 			File:       issue.File,
 			Line:       issue.Line,
+			Column:     column,
 			Message:    fmt.Sprintf("[gosec][%s] %s", issue.RuleID, issue.Details),
 			Severity:   sev,
 			Suggestion: issue.Cwe.URL,
 			Category:   "Security",
+			Rule:       issue.RuleID,
 		})
 	}
 	return findings
@@ -0,0 +1,195 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gotech-hub/gocheck/scanner"
+)
+
+// typedPackage is one package of the project checked with go/types.
+type typedPackage struct {
+	path  string // import path, or the directory outside a module
+	name  string
+	files []projectFile // test files included, except those of package x_test
+	pkg   *types.Package
+	info  *types.Info
+}
+
+// internal reports whether the package is an internal package, importable
+// only from within the module.
+func (tp *typedPackage) internal() bool {
+	return strings.HasPrefix(tp.path, "internal/") || strings.Contains(tp.path, "/internal/") || strings.HasSuffix(tp.path, "/internal")
+}
+
+// projectImporter imports the packages of the project from the project
+// itself, so every package sees the same objects, and other packages from
+// the compiler's export data.
+type projectImporter struct {
+	check  func(tp *typedPackage)
+	byPath map[string]*typedPackage
+	gc     types.Importer
+}
+
+func (im *projectImporter) Import(path string) (*types.Package, error) {
+	if tp, ok := im.byPath[path]; ok {
+		im.check(tp)
+		return tp.pkg, nil
+	}
+	return im.gc.Import(path)
+}
+
+// exportData locates the export data of the packages imported by the
+// project and their dependencies with a single go list, which builds what
+// is missing and may download modules. The default gc importer runs go list
+// once per package, which made every scan many times slower. Packages go
+// list cannot build are left out, the type checker then records what it can
+// without them, and a warning on stderr says that the rules built on types
+// are incomplete.
+func exportData(dir string, imports []string) map[string]string {
+	exports := map[string]string{}
+	if len(imports) == 0 {
+		return exports
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command("go", append([]string{"list", "-e", "-export", "-deps", "-json=ImportPath,Export,Error"}, imports...)...)
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		warnTypes(fmt.Sprintf("go list failed: %v: %s", err, strings.TrimSpace(stderr.String())))
+	}
+	var failed []string
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for {
+		var pkg struct {
+			ImportPath, Export string
+			Error              *struct{ Err string }
+		}
+		if dec.Decode(&pkg) != nil {
+			break
+		}
+		if pkg.Export != "" {
+			exports[pkg.ImportPath] = pkg.Export
+		} else if pkg.Error != nil {
+			failed = append(failed, pkg.ImportPath+": "+pkg.Error.Err)
+		}
+	}
+	if len(failed) > 0 {
+		warnTypes(fmt.Sprintf("go list cannot build %d package(s), first: %s", len(failed), failed[0]))
+	}
+	return exports
+}
+
+// warnTypes tells on stderr why type information is missing.
+func warnTypes(reason string) {
+	fmt.Fprintf(os.Stderr, "⚠️  Warning: rules that need type information report only what they can resolve, %s\n", reason)
+}
+
+// typecheck type-checks the packages of the project, once, and returns them
+// sorted by import path. Type errors are ignored, go/types still records
+// what it could resolve, so a package with a missing dependency is checked
+// as far as possible.
+func (p *project) typecheck() []*typedPackage {
+	if p.typed != nil {
+		return p.typed
+	}
+	byKey := map[string]*typedPackage{}
+	for _, f := range p.files {
+		name := f.node.Name.Name
+		key := f.dir + "\x00" + name
+		tp, ok := byKey[key]
+		if !ok {
+			tp = &typedPackage{path: packagePath(f.dir), name: name}
+			if strings.HasSuffix(name, "_test") {
+				tp.path += "_test"
+			}
+			byKey[key] = tp
+			p.typed = append(p.typed, tp)
+		}
+		tp.files = append(tp.files, f)
+	}
+
+	im := &projectImporter{byPath: map[string]*typedPackage{}}
+	for _, tp := range p.typed {
+		im.byPath[tp.path] = tp
+	}
+	seen := map[string]bool{}
+	var imports []string
+	for _, f := range p.files {
+		for _, spec := range f.node.Imports {
+			path, err := strconv.Unquote(spec.Path.Value)
+			if err != nil || path == "C" || path == "unsafe" || im.byPath[path] != nil || seen[path] {
+				continue
+			}
+			seen[path] = true
+			imports = append(imports, path)
+		}
+	}
+	sort.Strings(imports)
+	dir := "."
+	if len(p.files) > 0 {
+		dir = p.files[0].dir
+		if modRoot, _ := scanner.FindModule(dir); modRoot != "" {
+			dir = modRoot
+		}
+	}
+	exports := exportData(dir, imports)
+	im.gc = importer.ForCompiler(p.fset, "gc", func(path string) (io.ReadCloser, error) {
+		if file, ok := exports[path]; ok {
+			return os.Open(file)
+		}
+		return nil, fmt.Errorf("no export data for %s", path)
+	})
+	conf := types.Config{Importer: im, Error: func(error) {}}
+	im.check = func(tp *typedPackage) {
+		if tp.pkg != nil {
+			return
+		}
+		tp.pkg = types.NewPackage(tp.path, tp.name)
+		tp.info = &types.Info{
+			Types:      map[ast.Expr]types.TypeAndValue{},
+			Defs:       map[*ast.Ident]types.Object{},
+			Uses:       map[*ast.Ident]types.Object{},
+			Selections: map[*ast.SelectorExpr]*types.Selection{},
+			Implicits:  map[ast.Node]types.Object{},
+			Scopes:     map[ast.Node]*types.Scope{},
+		}
+		files := make([]*ast.File, len(tp.files))
+		for i, f := range tp.files {
+			files[i] = f.node
+		}
+		types.NewChecker(&conf, p.fset, tp.pkg, tp.info).Files(files)
+	}
+	for _, tp := range p.typed {
+		im.check(tp)
+	}
+	sort.Slice(p.typed, func(i, j int) bool { return p.typed[i].path < p.typed[j].path })
+	return p.typed
+}
+
+// packagePath returns the import path of the package in dir, or dir itself
+// when it is not part of a module.
+func packagePath(dir string) string {
+	modRoot, modPath := scanner.FindModule(dir)
+	abs, err := filepath.Abs(dir)
+	if modRoot == "" || err != nil {
+		return filepath.ToSlash(dir)
+	}
+	rel, err := filepath.Rel(modRoot, abs)
+	if err != nil || rel == "." {
+		return modPath
+	}
+	return modPath + "/" + filepath.ToSlash(rel)
+}
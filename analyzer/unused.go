@@ -0,0 +1,386 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+)
+
+// analyzeUnused implements Rule 8 with type information: unused local
+// variables, unused parameters, ineffectual assignments and unused named
+// results. Generated files are skipped.
+func analyzeUnused(p *project) []Finding {
+	var results []Finding
+	pkgs := p.typecheck()
+	ifaces := interfacesOf(pkgs)
+	for _, tp := range pkgs {
+		uses := packageUses(tp, ifaces)
+		for _, f := range tp.files {
+			if f.generated {
+				continue
+			}
+			results = append(results, unusedInFile(p.fset, tp, f, uses)...)
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return lessLocation(Location{File: results[i].File, Line: results[i].Line}, Location{File: results[j].File, Line: results[j].Line})
+	})
+	return results
+}
+
+// uses records how the objects of a package are referenced.
+type uses struct {
+	used    map[types.Object]bool // referenced at least once
+	escapes map[types.Object]bool // functions used as values, their signature is imposed
+	ifaces  []*types.Interface    // interfaces a method may implement, see interfacesOf
+}
+
+func packageUses(tp *typedPackage, ifaces []*types.Interface) *uses {
+	u := &uses{used: map[types.Object]bool{}, escapes: map[types.Object]bool{}, ifaces: ifaces}
+	callees := map[*ast.Ident]bool{}
+	for _, f := range tp.files {
+		ast.Inspect(f.node, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok {
+				switch fun := ast.Unparen(call.Fun).(type) {
+				case *ast.Ident:
+					callees[fun] = true
+				case *ast.SelectorExpr:
+					callees[fun.Sel] = true
+				}
+			}
+			return true
+		})
+	}
+	for id, obj := range tp.info.Uses {
+		u.used[obj] = true
+		if _, ok := obj.(*types.Func); ok && !callees[id] {
+			u.escapes[obj] = true
+		}
+	}
+	return u
+}
+
+// interfacesOf returns the interfaces a method of the project may implement:
+// those used in any package of the project, which includes the interfaces
+// of a package importing the method's own, and those declared by the
+// packages they import.
+func interfacesOf(pkgs []*typedPackage) []*types.Interface {
+	seen := map[*types.Interface]bool{}
+	var ifaces []*types.Interface
+	add := func(t types.Type) {
+		if it, ok := t.Underlying().(*types.Interface); ok && it.NumMethods() > 0 && !seen[it] {
+			seen[it] = true
+			ifaces = append(ifaces, it)
+		}
+	}
+	addScope := func(scope *types.Scope) {
+		for _, name := range scope.Names() {
+			if tn, ok := scope.Lookup(name).(*types.TypeName); ok {
+				add(tn.Type())
+			}
+		}
+	}
+	for _, tp := range pkgs {
+		for _, tv := range tp.info.Types {
+			if tv.Type != nil {
+				add(tv.Type)
+			}
+		}
+		addScope(tp.pkg.Scope())
+		for _, imp := range tp.pkg.Imports() {
+			addScope(imp.Scope())
+		}
+	}
+	return ifaces
+}
+
+// implementsInterface reports whether the method fn is part of an
+// interface implementation, in which case its signature is not its own.
+func implementsInterface(fn *types.Func, ifaces []*types.Interface) bool {
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return false
+	}
+	t := recv.Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	for _, it := range ifaces {
+		has := false
+		for i := 0; i < it.NumMethods(); i++ {
+			if it.Method(i).Name() == fn.Name() {
+				has = true
+				break
+			}
+		}
+		if has && (types.Implements(t, it) || types.Implements(types.NewPointer(t), it)) {
+			return true
+		}
+	}
+	return false
+}
+
+func unusedInFile(fset *token.FileSet, tp *typedPackage, f projectFile, u *uses) []Finding {
+	var results []Finding
+	report := func(pos token.Pos, severity Severity, rule, message, suggestion string) {
+		results = append(results, newFinding(fset, f.path, pos, severity, "Clean", rule, message, suggestion))
+	}
+	info, used := tp.info, u.used
+
+	// receivers, parameters and results, also of func-typed variables and
+	// fields, are not local variables
+	params := map[types.Object]bool{}
+	ast.Inspect(f.node, func(n ast.Node) bool {
+		if ft, ok := n.(*ast.FuncType); ok {
+			for _, fields := range []*ast.FieldList{ft.Params, ft.Results} {
+				if fields == nil {
+					continue
+				}
+				for _, field := range fields.List {
+					for _, id := range field.Names {
+						params[info.Defs[id]] = true
+					}
+				}
+			}
+		}
+		return true
+	})
+	for _, unit := range funcUnits(fset, f.node) {
+		var fn *types.Func
+		name := unit.Name
+		if unit.Decl != nil {
+			fn, _ = info.Defs[unit.Decl.Name].(*types.Func)
+		}
+		fieldObjs := func(fields *ast.FieldList) []*ast.Ident {
+			var ids []*ast.Ident
+			if fields != nil {
+				for _, field := range fields.List {
+					ids = append(ids, field.Names...)
+				}
+			}
+			return ids
+		}
+		if unit.Decl != nil {
+			for _, id := range fieldObjs(unit.Decl.Recv) {
+				params[info.Defs[id]] = true
+			}
+		}
+
+		// Rule 8b: Unused parameters. Function literals, functions used as
+		// values and interface methods have their signature imposed.
+		checkParams := unit.Decl != nil && len(unit.Body.List) > 0 && fn != nil &&
+			!u.escapes[fn] && !implementsInterface(fn, u.ifaces) && !(f.test && isTestFunc(fn.Name()))
+		if checkParams {
+			for _, id := range fieldObjs(unit.Type.Params) {
+				if obj := info.Defs[id]; obj != nil && id.Name != "_" && !used[obj] {
+					report(id.Pos(), Low, "unused-param", fmt.Sprintf("Parameter '%s' of %s is never used", id.Name, name),
+						"Remove the parameter, or name it _ if the signature has to stay.")
+				}
+			}
+		}
+
+		// Rule 8d: Named results assigned in the body but never returned. A
+		// name alone documents the result and is fine; a value assigned to it
+		// and then replaced by an explicit return is lost. A bare return, or a
+		// deferred function reading the result, returns it.
+		for _, id := range fieldObjs(unit.Type.Results) {
+			obj := info.Defs[id]
+			if obj == nil || id.Name == "_" {
+				continue
+			}
+			if assigned := resultAssigned(info, unit.Body, obj); assigned != nil {
+				report(assigned.Pos(), Low, "unused-result", fmt.Sprintf("Value assigned to named result '%s' of %s is never returned", id.Name, name),
+					fmt.Sprintf("Return %s, or drop the assignment: every return of %s lists its values explicitly.", id.Name, name))
+			}
+		}
+
+		// Rule 8c: Ineffectual assignments
+		results = append(results, ineffectualAssigns(fset, info, f.path, unit.Body)...)
+	}
+
+	// Rule 8a: Unused local variables
+	scope := tp.pkg.Scope()
+	for id, obj := range info.Defs {
+		v, ok := obj.(*types.Var)
+		if !ok || v.IsField() || v.Parent() == scope || params[obj] || id.Name == "_" || used[obj] {
+			continue
+		}
+		if fset.Position(id.Pos()).Filename != f.path {
+			continue
+		}
+		report(id.Pos(), Low, "unused-var", fmt.Sprintf("Local variable '%s' declared but not used", id.Name),
+			"Remove unused local variable.")
+	}
+	return results
+}
+
+// resultAssigned returns the first assignment to the named result obj in
+// body when obj is never read and no bare return returns it, nil otherwise.
+func resultAssigned(info *types.Info, body *ast.BlockStmt, obj types.Object) *ast.Ident {
+	var assigned *ast.Ident
+	lhs := map[*ast.Ident]bool{} // plain assignments, they do not read
+	read, bare := false, false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ReturnStmt:
+			bare = bare || len(n.Results) == 0
+		case *ast.AssignStmt:
+			if n.Tok != token.ASSIGN {
+				break
+			}
+			for _, e := range n.Lhs {
+				if id, ok := e.(*ast.Ident); ok && info.Uses[id] == obj {
+					lhs[id] = true
+					if assigned == nil {
+						assigned = id
+					}
+				}
+			}
+		case *ast.Ident:
+			if info.Uses[n] == obj && !lhs[n] {
+				read = true
+			}
+		}
+		return true
+	})
+	if read || bare {
+		return nil
+	}
+	return assigned
+}
+
+// leaves reports whether stmt may leave the straight flow of its block: a
+// return, break, continue or goto anywhere in it, or a label to jump to.
+// The value assigned before it may then be read elsewhere.
+func leaves(stmt ast.Stmt) bool {
+	found := false
+	ast.Inspect(stmt, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt, *ast.BranchStmt, *ast.LabeledStmt:
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// ineffectualAssigns reports assignments to local variables whose value is
+// overwritten by a later statement of the same block before being read.
+// Variables captured by a function literal or whose address is taken are
+// left alone, they can be read behind the scenes.
+func ineffectualAssigns(fset *token.FileSet, info *types.Info, file string, body *ast.BlockStmt) []Finding {
+	escaped := map[types.Object]bool{}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			ast.Inspect(n.Body, func(n ast.Node) bool {
+				if id, ok := n.(*ast.Ident); ok && info.Uses[id] != nil {
+					escaped[info.Uses[id]] = true
+				}
+				return true
+			})
+			return false
+		case *ast.UnaryExpr:
+			if id, ok := ast.Unparen(n.X).(*ast.Ident); ok && n.Op == token.AND {
+				escaped[info.ObjectOf(id)] = true
+			}
+		}
+		return true
+	})
+
+	// reads counts the references to obj in n other than plain assignments to it
+	reads := func(n ast.Node, obj types.Object) int {
+		count := 0
+		ast.Inspect(n, func(n ast.Node) bool {
+			if as, ok := n.(*ast.AssignStmt); ok && (as.Tok == token.ASSIGN || as.Tok == token.DEFINE) {
+				for _, rhs := range as.Rhs {
+					ast.Inspect(rhs, func(n ast.Node) bool {
+						if id, ok := n.(*ast.Ident); ok && info.ObjectOf(id) == obj {
+							count++
+						}
+						return true
+					})
+				}
+				for _, lhs := range as.Lhs {
+					if _, ok := lhs.(*ast.Ident); !ok {
+						ast.Inspect(lhs, func(n ast.Node) bool {
+							if id, ok := n.(*ast.Ident); ok && info.ObjectOf(id) == obj {
+								count++
+							}
+							return true
+						})
+					}
+				}
+				return false
+			}
+			if id, ok := n.(*ast.Ident); ok && info.Uses[id] == obj {
+				count++
+			}
+			return true
+		})
+		return count
+	}
+	// assigns returns the local variables stmt assigns without reading them
+	assigns := func(stmt ast.Stmt) []*ast.Ident {
+		as, ok := stmt.(*ast.AssignStmt)
+		if !ok || (as.Tok != token.ASSIGN && as.Tok != token.DEFINE) {
+			return nil
+		}
+		var vars []*ast.Ident
+		for _, lhs := range as.Lhs {
+			id, ok := lhs.(*ast.Ident)
+			if !ok || id.Name == "_" {
+				continue
+			}
+			if v, ok := info.ObjectOf(id).(*types.Var); ok && !v.IsField() && v.Parent() != v.Pkg().Scope() && !escaped[v] {
+				vars = append(vars, id)
+			}
+		}
+		return vars
+	}
+
+	var results []Finding
+	check := func(list []ast.Stmt) {
+		for i, stmt := range list {
+			for _, id := range assigns(stmt) {
+				obj := info.ObjectOf(id)
+			next:
+				for _, later := range list[i+1:] {
+					if reads(later, obj) > 0 {
+						break
+					}
+					if leaves(later) {
+						break next
+					}
+					for _, over := range assigns(later) {
+						if info.ObjectOf(over) != obj {
+							continue
+						}
+						results = append(results, newFinding(fset, file, id.Pos(), Medium, "Clean", "ineffectual-assign",
+							fmt.Sprintf("Value assigned to '%s' is never read, it is overwritten at line %d", id.Name, fset.Position(over.Pos()).Line),
+							"Remove the first assignment, or use the value before assigning again (a missing error check?)."))
+						break next
+					}
+				}
+			}
+		}
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.BlockStmt:
+			check(n.List)
+		case *ast.CaseClause:
+			check(n.Body)
+		case *ast.CommClause:
+			check(n.Body)
+		}
+		return true
+	})
+	return results
+}
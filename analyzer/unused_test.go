@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"testing"
+)
+
+func TestIneffectualAssigns(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []int // lines of the ineffectual assignments, line 1 is func f
+	}{
+		{"overwritten", `
+	x := 1
+	x = 2
+	print(x)`, []int{2}},
+		{"read in between", `
+	x := 1
+	print(x)
+	x = 2
+	print(x)`, nil},
+		{"read on the right", `
+	x := 1
+	x = x + 1
+	print(x)`, nil},
+		{"return before", `
+	x := 1
+	if len(s) > 0 {
+		return
+	}
+	x = 2
+	print(x)`, nil},
+		{"continue in an if", `
+	for range s {
+		x := 1
+		if len(s) > 1 {
+			continue
+		}
+		x = 2
+		print(x)
+	}`, nil},
+		{"label before", `
+	x := 1
+again:
+	x = 2
+	print(x)
+	goto again`, nil},
+		{"captured by a closure", `
+	x := 1
+	g := func() { print(x) }
+	x = 2
+	g()`, nil},
+		{"address taken", `
+	x := 1
+	p := &x
+	x = 2
+	print(*p)`, nil},
+		{"case clause", `
+	switch len(s) {
+	case 0:
+		y := 1
+		y = 2
+		print(y)
+	}`, []int{4}},
+		{"nested block", `
+	if len(s) > 0 {
+		z := "a"
+		z = "b"
+		print(z)
+	}`, []int{3}},
+		{"field assignment", `
+	var v struct{ n int }
+	v.n = 1
+	v.n = 2
+	print(v.n)`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := "package x\n\nfunc f(s []int) {" + tt.body + "\n}\n"
+			fset := token.NewFileSet()
+			node, err := parser.ParseFile(fset, "x.go", src, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			info := &types.Info{Types: map[ast.Expr]types.TypeAndValue{}, Defs: map[*ast.Ident]types.Object{}, Uses: map[*ast.Ident]types.Object{}}
+			if _, err := (&types.Config{}).Check("x", fset, []*ast.File{node}, info); err != nil {
+				t.Fatal(err)
+			}
+			body := node.Decls[0].(*ast.FuncDecl).Body
+			var got []int
+			for _, f := range ineffectualAssigns(fset, info, "x.go", body) {
+				got = append(got, f.Line-2)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got lines %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
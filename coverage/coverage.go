@@ -0,0 +1,165 @@
+package coverage
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gotech-hub/gocheck/analyzer"
+	"github.com/gotech-hub/gocheck/metrics"
+	"github.com/gotech-hub/gocheck/scanner"
+)
+
+// UntestedComplexity is the cyclomatic complexity from which an untested
+// function is considered risky enough to raise the severity of its findings.
+const UntestedComplexity = 10
+
+// Block is one block of statements of a coverage profile.
+type Block struct {
+	StartLine, StartCol int
+	EndLine, EndCol     int
+	Statements          int
+	Count               int // times the block ran, or 0/1 in "set" mode
+}
+
+// Profile is a Go coverage profile, as written by go test -coverprofile,
+// with its file names resolved to absolute paths.
+type Profile struct {
+	Mode  string
+	Files map[string][]Block
+}
+
+// Load reads the coverage profile at path. File names in a profile are
+// import paths; they are resolved against the module containing root.
+func Load(path, root string) (*Profile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	modDir, modPath := scanner.FindModule(root)
+	p := &Profile{Files: map[string][]Block{}}
+	seen := map[string]int{} // index of each block, blocks repeat when several test binaries cover a package
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		if mode, ok := strings.CutPrefix(line, "mode:"); ok {
+			p.Mode = strings.TrimSpace(mode)
+			continue
+		}
+		// "github.com/org/repo/pkg/file.go:12.34,15.2 3 1"
+		colon := strings.LastIndex(line, ":")
+		if colon < 0 {
+			return nil, fmt.Errorf("%s:%d: invalid coverage line", path, n)
+		}
+		var b Block
+		if _, err := fmt.Sscanf(line[colon+1:], "%d.%d,%d.%d %d %d", &b.StartLine, &b.StartCol, &b.EndLine, &b.EndCol, &b.Statements, &b.Count); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid coverage line: %v", path, n, err)
+		}
+		file := resolve(line[:colon], modDir, modPath)
+		key := fmt.Sprintf("%s:%d.%d,%d.%d", file, b.StartLine, b.StartCol, b.EndLine, b.EndCol)
+		if i, ok := seen[key]; ok {
+			p.Files[file][i].Count = max(p.Files[file][i].Count, b.Count)
+			continue
+		}
+		seen[key] = len(p.Files[file])
+		p.Files[file] = append(p.Files[file], b)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if p.Mode == "" {
+		return nil, fmt.Errorf("%s is not a Go coverage profile", path)
+	}
+	return p, nil
+}
+
+// resolve maps a profile file name to an absolute path. Names outside the
+// module are kept as they are.
+func resolve(name, modDir, modPath string) string {
+	switch {
+	case modPath != "" && strings.HasPrefix(name, modPath+"/"):
+		return filepath.Join(modDir, filepath.FromSlash(strings.TrimPrefix(name, modPath+"/")))
+	case strings.HasPrefix(name, "_/"):
+		// packages outside GOPATH and modules are written as "_/abs/path"
+		return filepath.FromSlash(strings.TrimPrefix(name, "_"))
+	}
+	return name
+}
+
+// Lines returns the covered and total number of statements in the blocks of
+// file that start between the lines start and end. ok is false when the
+// profile does not include file.
+func (p *Profile) Lines(file string, start, end int) (covered, total int, ok bool) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return 0, 0, false
+	}
+	blocks, ok := p.Files[abs]
+	if !ok {
+		return 0, 0, false
+	}
+	for _, b := range blocks {
+		if b.StartLine < start || b.StartLine > end {
+			continue
+		}
+		total += b.Statements
+		if b.Count > 0 {
+			covered += b.Statements
+		}
+	}
+	return covered, total, true
+}
+
+// File returns the covered and total number of statements of file.
+func (p *Profile) File(file string) (covered, total int, ok bool) {
+	return p.Lines(file, 0, int(^uint(0)>>1))
+}
+
+// Annotate sets the coverage of every finding to the statement coverage of
+// the function it is in. Findings in untested functions that are complex or
+// security-sensitive are raised one severity level, as nothing would catch a
+// regression while fixing them.
+func (p *Profile) Annotate(findings []analyzer.Finding) {
+	funcs := map[string][]metrics.Func{}
+	for i := range findings {
+		f := &findings[i]
+		fns, ok := funcs[f.File]
+		if !ok {
+			fns, _ = metrics.FileFuncs(f.File)
+			funcs[f.File] = fns
+		}
+		for _, fn := range fns {
+			if f.Line < fn.Line || f.Line > fn.EndLine {
+				continue
+			}
+			covered, total, ok := p.Lines(f.File, fn.Line, fn.EndLine)
+			if !ok || total == 0 {
+				break
+			}
+			pct := float64(covered) * 100 / float64(total)
+			f.Coverage = &pct
+			if covered == 0 && (fn.Complexity >= UntestedComplexity || f.Category == "Security") {
+				f.Severity = raise(f.Severity)
+				f.Suggestion += fmt.Sprintf(" Function %s has no test coverage; add tests before changing it.", fn.Name)
+			}
+			break
+		}
+	}
+}
+
+func raise(s analyzer.Severity) analyzer.Severity {
+	switch s {
+	case analyzer.Low:
+		return analyzer.Medium
+	case analyzer.Medium:
+		return analyzer.High
+	}
+	return analyzer.Critical
+}
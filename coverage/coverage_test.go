@@ -0,0 +1,75 @@
+package coverage
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/m\n\ngo 1.23\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		profile  string
+		want     map[string][]Block // file relative to root
+		external bool               // files outside the module keep the name of the profile
+		wantErr  bool
+	}{
+		{
+			name: "set mode",
+			profile: "mode: set\n" +
+				"example.com/m/pkg/a.go:3.14,5.2 2 1\n" +
+				"example.com/m/pkg/a.go:7.20,9.3 1 0\n",
+			want: map[string][]Block{"pkg/a.go": {{3, 14, 5, 2, 2, 1}, {7, 20, 9, 3, 1, 0}}},
+		},
+		{
+			name: "repeated blocks keep the highest count",
+			profile: "mode: count\n" +
+				"example.com/m/a.go:3.14,5.2 2 0\n" +
+				"example.com/m/a.go:3.14,5.2 2 4\n" +
+				"example.com/m/a.go:3.14,5.2 2 1\n",
+			want: map[string][]Block{"a.go": {{3, 14, 5, 2, 2, 4}}},
+		},
+		{
+			name:     "outside the module",
+			profile:  "mode: atomic\n\nother.org/x/b.go:1.1,2.2 1 1\n",
+			want:     map[string][]Block{"other.org/x/b.go": {{1, 1, 2, 2, 1, 1}}},
+			external: true,
+		},
+		{name: "no mode line", profile: "example.com/m/a.go:3.14,5.2 2 1\n", wantErr: true},
+		{name: "no position", profile: "mode: set\nexample.com/m/a.go 2 1\n", wantErr: true},
+		{name: "bad numbers", profile: "mode: set\nexample.com/m/a.go:3.x,5.2 2 1\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cover.out")
+			if err := os.WriteFile(path, []byte(tt.profile), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			p, err := Load(path, root)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Load succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := map[string][]Block{}
+			for file, blocks := range tt.want {
+				if !tt.external {
+					file = filepath.Join(root, filepath.FromSlash(file))
+				}
+				want[file] = blocks
+			}
+			if !reflect.DeepEqual(p.Files, want) {
+				t.Errorf("got %v, want %v", p.Files, want)
+			}
+		})
+	}
+}
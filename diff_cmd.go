@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gotech-hub/gocheck/report"
+)
+
+// runDiff implements `gocheck diff`, which compares two JSON reports and
+// prints the fixed, new and unchanged findings.
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	format := fs.String("format", "text", "Output format: text, json, markdown or html")
+	out := fs.String("out", "", "Write the diff to this file instead of stdout")
+	fs.Usage = func() {
+		fmt.Println("Usage:")
+		fmt.Println("  gocheck diff [--format text|json|markdown|html] [--out file] old.json new.json")
+	}
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("diff needs two JSON reports")
+	}
+
+	old, err := report.LoadJSON(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	new, err := report.LoadJSON(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	d := report.Diff(old, new)
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch *format {
+	case "text":
+		report.WriteDiffText(w, d)
+	case "json":
+		return report.WriteDiffJSON(w, d)
+	case "markdown", "md":
+		report.WriteDiffMarkdown(w, d)
+	case "html":
+		return report.WriteDiffHTML(w, d)
+	default:
+		return fmt.Errorf("unknown diff format %q", *format)
+	}
+	return nil
+}
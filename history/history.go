@@ -0,0 +1,152 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gotech-hub/gocheck/analyzer"
+)
+
+// DefaultPath is the history file used by `gocheck history` when no --file
+// is given, and the suggested value for `gocheck --history`.
+const DefaultPath = ".gocheck/history.jsonl"
+
+// Run is the summary of one gocheck run. Runs are stored one JSON object per
+// line so that appending never has to rewrite the file.
+type Run struct {
+	Timestamp  time.Time      `json:"timestamp"`
+	Commit     string         `json:"commit,omitempty"`
+	Total      int            `json:"total"`
+	BySeverity map[string]int `json:"by_severity"`
+	ByCategory map[string]int `json:"by_category"`
+	ByRule     map[string]int `json:"by_rule"`
+}
+
+// Summarize counts findings per severity, category and rule.
+func Summarize(findings []analyzer.Finding, commit string, at time.Time) Run {
+	run := Run{
+		Timestamp:  at,
+		Commit:     commit,
+		Total:      len(findings),
+		BySeverity: map[string]int{},
+		ByCategory: map[string]int{},
+		ByRule:     map[string]int{},
+	}
+	for _, f := range findings {
+		run.BySeverity[string(f.Severity)]++
+		run.ByCategory[f.Category]++
+		run.ByRule[f.Rule]++
+	}
+	return run
+}
+
+// Load reads every run recorded in path, oldest first. A missing file is
+// not an error and yields no runs.
+func Load(path string) ([]Run, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var runs []Run
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var run Run
+		if err := json.Unmarshal(sc.Bytes(), &run); err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, sc.Err()
+}
+
+// Append records run at the end of path, creating the file and its
+// directory if needed.
+func Append(path string, run Run) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	line, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// Delta is the change of one counter between two runs.
+type Delta struct {
+	Key string
+	Old int
+	New int
+}
+
+// Change returns New - Old; negative values mean the codebase improved.
+func (d Delta) Change() int {
+	return d.New - d.Old
+}
+
+// Comparison holds the counter changes between two runs.
+type Comparison struct {
+	Total      Delta
+	BySeverity []Delta
+	ByCategory []Delta
+	ByRule     []Delta
+}
+
+// Compare returns the changes from old to new. Rules and categories are
+// sorted by the size of their change, largest regression first.
+func Compare(old, new Run) Comparison {
+	return Comparison{
+		Total:      Delta{Key: "Total", Old: old.Total, New: new.Total},
+		BySeverity: severityDeltas(old.BySeverity, new.BySeverity),
+		ByCategory: deltas(old.ByCategory, new.ByCategory),
+		ByRule:     deltas(old.ByRule, new.ByRule),
+	}
+}
+
+func severityDeltas(old, new map[string]int) []Delta {
+	var result []Delta
+	for _, s := range []analyzer.Severity{analyzer.Critical, analyzer.High, analyzer.Medium, analyzer.Low} {
+		result = append(result, Delta{Key: string(s), Old: old[string(s)], New: new[string(s)]})
+	}
+	return result
+}
+
+func deltas(old, new map[string]int) []Delta {
+	keys := map[string]bool{}
+	for k := range old {
+		keys[k] = true
+	}
+	for k := range new {
+		keys[k] = true
+	}
+	var result []Delta
+	for k := range keys {
+		result = append(result, Delta{Key: k, Old: old[k], New: new[k]})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Change() != result[j].Change() {
+			return result[i].Change() > result[j].Change()
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
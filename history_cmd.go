@@ -0,0 +1,124 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gotech-hub/gocheck/history"
+	"github.com/gotech-hub/gocheck/vcs"
+)
+
+// runHistory implements `gocheck history`, which lists the recorded runs or
+// compares two of them.
+func runHistory(args []string) error {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	file := fs.String("file", history.DefaultPath, "History file to read")
+	fs.Usage = func() {
+		fmt.Println("Usage:")
+		fmt.Println("  gocheck history [--file path] [list]")
+		fmt.Println("  gocheck history [--file path] compare <run> <run>")
+		fmt.Println("")
+		fmt.Println("A run is its number in the list (negative numbers count from the latest run)")
+		fmt.Println("or a commit hash prefix.")
+	}
+	fs.Parse(args)
+
+	runs, err := history.Load(*file)
+	if err != nil {
+		return fmt.Errorf("cannot read history %s: %v", *file, err)
+	}
+	if len(runs) == 0 {
+		return fmt.Errorf("no runs recorded in %s, run gocheck with --history first", *file)
+	}
+
+	switch fs.Arg(0) {
+	case "", "list":
+		listRuns(runs)
+		return nil
+	case "compare":
+		if fs.NArg() != 3 {
+			fs.Usage()
+			return fmt.Errorf("compare needs two runs")
+		}
+		oldIdx, err := findRun(runs, fs.Arg(1))
+		if err != nil {
+			return err
+		}
+		newIdx, err := findRun(runs, fs.Arg(2))
+		if err != nil {
+			return err
+		}
+		printComparison(runs, oldIdx, newIdx)
+		return nil
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown history command %q", fs.Arg(0))
+	}
+}
+
+func listRuns(runs []history.Run) {
+	fmt.Printf("%-4s %-17s %-9s %7s %9s %6s %7s %5s\n", "#", "Date", "Commit", "Total", "Critical", "High", "Medium", "Low")
+	for i, r := range runs {
+		fmt.Printf("%-4d %-17s %-9s %7d %9d %6d %7d %5d\n", i+1, r.Timestamp.Format("2006-01-02 15:04"), commitLabel(r.Commit),
+			r.Total, r.BySeverity["Critical"], r.BySeverity["High"], r.BySeverity["Medium"], r.BySeverity["Low"])
+	}
+}
+
+// findRun resolves a run reference given on the command line to an index
+// into runs.
+func findRun(runs []history.Run, ref string) (int, error) {
+	if n, err := strconv.Atoi(ref); err == nil {
+		if n < 0 {
+			n += len(runs) + 1
+		}
+		if n < 1 || n > len(runs) {
+			return 0, fmt.Errorf("run %s out of range (1-%d)", ref, len(runs))
+		}
+		return n - 1, nil
+	}
+	for i := len(runs) - 1; i >= 0; i-- {
+		if runs[i].Commit != "" && strings.HasPrefix(runs[i].Commit, ref) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("no run recorded for commit %s", ref)
+}
+
+func printComparison(runs []history.Run, oldIdx, newIdx int) {
+	old, new := runs[oldIdx], runs[newIdx]
+	cmp := history.Compare(old, new)
+	fmt.Printf("Comparing run #%d (%s %s) → run #%d (%s %s)\n\n",
+		oldIdx+1, old.Timestamp.Format("2006-01-02 15:04"), commitLabel(old.Commit),
+		newIdx+1, new.Timestamp.Format("2006-01-02 15:04"), commitLabel(new.Commit))
+	printDeltas("Severity", append([]history.Delta{cmp.Total}, cmp.BySeverity...), true)
+	printDeltas("Category", cmp.ByCategory, false)
+	printDeltas("Rule", cmp.ByRule, false)
+}
+
+// printDeltas prints one table of the comparison. Unless all is set, rows
+// whose count did not change are left out.
+func printDeltas(title string, deltas []history.Delta, all bool) {
+	fmt.Printf("%-28s %7s %7s %7s\n", title, "Old", "New", "Change")
+	printed := 0
+	for _, d := range deltas {
+		if !all && d.Change() == 0 {
+			continue
+		}
+		fmt.Printf("%-28s %7d %7d %+7d\n", d.Key, d.Old, d.New, d.Change())
+		printed++
+	}
+	if printed == 0 {
+		fmt.Println("  (no changes)")
+	}
+	fmt.Println("")
+}
+
+// commitLabel is the short commit hash of a run, "-" when it has none.
+func commitLabel(commit string) string {
+	if commit == "" {
+		return "-"
+	}
+	return vcs.ShortHash(commit)
+}
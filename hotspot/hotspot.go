@@ -0,0 +1,88 @@
+package hotspot
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/gotech-hub/gocheck/analyzer"
+	"github.com/gotech-hub/gocheck/metrics"
+	"github.com/gotech-hub/gocheck/vcs"
+)
+
+// Hotspot is a file or function ranked by how often it changes and how
+// complex it is. Code that is both is where refactoring pays off most.
+type Hotspot struct {
+	File       string `json:"file"`
+	Func       string `json:"func,omitempty"` // empty for file-level hotspots
+	Line       int    `json:"line,omitempty"`
+	Churn      int    `json:"churn"`      // commits that changed it in the window
+	Complexity int    `json:"complexity"` // cyclomatic complexity, summed over the functions of a file
+	Findings   int    `json:"findings"`
+	Score      int    `json:"score"` // churn * (complexity + findings)
+	Hot        bool   `json:"hot"`   // in the upper half of both churn and complexity
+}
+
+// Analyze ranks the given files, or their functions when byFunc is set, by
+// churn since the given date times complexity plus findings, highest first.
+// root must be inside a git repository.
+func Analyze(root, since string, files []string, findings []analyzer.Finding, byFunc bool) ([]Hotspot, error) {
+	churn, err := vcs.Churn(root, since)
+	if err != nil {
+		return nil, err
+	}
+	// findings per absolute file path, with their lines to attribute them to functions
+	findingLines := map[string][]int{}
+	for _, f := range findings {
+		abs, _ := filepath.Abs(f.File)
+		findingLines[abs] = append(findingLines[abs], f.Line)
+	}
+
+	var hotspots []Hotspot
+	for _, file := range files {
+		abs, _ := filepath.Abs(file)
+		funcs, err := metrics.FileFuncs(file)
+		if err != nil {
+			continue
+		}
+		c := churn[abs]
+		if c == nil {
+			c = &vcs.FileChurn{}
+		}
+		if !byFunc {
+			h := Hotspot{File: file, Churn: c.Commits, Findings: len(findingLines[abs])}
+			for _, fn := range funcs {
+				h.Complexity += fn.Complexity
+			}
+			hotspots = append(hotspots, h)
+			continue
+		}
+		for _, fn := range funcs {
+			h := Hotspot{File: file, Func: fn.Name, Line: fn.Line, Churn: c.Funcs[fn.Name], Complexity: fn.Complexity}
+			for _, line := range findingLines[abs] {
+				if line >= fn.Line && line <= fn.EndLine {
+					h.Findings++
+				}
+			}
+			hotspots = append(hotspots, h)
+		}
+	}
+
+	var maxChurn, maxComplexity int
+	for i := range hotspots {
+		h := &hotspots[i]
+		h.Score = h.Churn * (h.Complexity + h.Findings)
+		maxChurn = max(maxChurn, h.Churn)
+		maxComplexity = max(maxComplexity, h.Complexity)
+	}
+	for i := range hotspots {
+		h := &hotspots[i]
+		h.Hot = h.Churn > 0 && 2*h.Churn >= maxChurn && 2*h.Complexity >= maxComplexity
+	}
+	sort.SliceStable(hotspots, func(i, j int) bool {
+		if hotspots[i].Score != hotspots[j].Score {
+			return hotspots[i].Score > hotspots[j].Score
+		}
+		return hotspots[i].Churn > hotspots[j].Churn
+	})
+	return hotspots, nil
+}
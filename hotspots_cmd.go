@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/gotech-hub/gocheck/analyzer"
+	"github.com/gotech-hub/gocheck/hotspot"
+	"github.com/gotech-hub/gocheck/report"
+	"github.com/gotech-hub/gocheck/scanner"
+	"github.com/gotech-hub/gocheck/vcs"
+)
+
+// runHotspots implements `gocheck hotspots`, which ranks files or functions
+// by git churn combined with complexity and finding counts.
+func runHotspots(args []string) error {
+	fs := flag.NewFlagSet("hotspots", flag.ExitOnError)
+	path := fs.String("path", ".", "Path to scan")
+	since := fs.String("since", vcs.DefaultChurnSince, "Only count commits after this date (anything git log --since accepts)")
+	by := fs.String("by", "file", "Rank files or functions: file or func")
+	top := fs.Int("top", 20, "Number of hotspots to print, 0 for all")
+	format := fs.String("format", "text", "Output format: text or json")
+	fromReport := fs.String("report", "", "Take finding counts from this JSON report instead of analyzing the code")
+	analyzerCfg := analyzerFlags(fs)
+	fs.Usage = func() {
+		fmt.Println("Usage:")
+		fmt.Println("  gocheck hotspots [--path dir] [--since date] [--by file|func] [--top n] [--format text|json] [--report report.json]")
+		fmt.Println("                   [--max-cyclomatic n] [--max-cognitive n] [--func-length metric] [--max-func-length n]")
+	}
+	fs.Parse(args)
+
+	if _, err := os.Stat(*path); err != nil {
+		return fmt.Errorf("invalid path %s: %w", *path, err)
+	}
+	if *by != "file" && *by != "func" {
+		return fmt.Errorf("--by must be file or func, not %q", *by)
+	}
+	if err := analyzerCfg.Validate(); err != nil {
+		return fmt.Errorf("--func-length: %w", err)
+	}
+
+	files := scanner.ScanDir(*path)
+	var findings []analyzer.Finding
+	if *fromReport != "" {
+		var err error
+		if findings, err = report.LoadJSON(*fromReport); err != nil {
+			return err
+		}
+	} else {
+		// the finding counts must match those of a normal scan
+		if missing := missingTools(); len(missing) > 0 {
+			return fmt.Errorf("the following required tools are missing: %v; install them as described in the README or pass --report", missing)
+		}
+		findings = analyzeFiles(*path, files, *analyzerCfg)
+	}
+	hotspots, err := hotspot.Analyze(*path, *since, files, findings, *by == "func")
+	if err != nil {
+		return fmt.Errorf("cannot read git history of %s: %w", *path, err)
+	}
+	if *top > 0 && len(hotspots) > *top {
+		hotspots = hotspots[:*top]
+	}
+
+	switch *format {
+	case "text":
+		report.WriteHotspotsText(os.Stdout, hotspots, *path)
+	case "json":
+		return report.WriteHotspotsJSON(os.Stdout, hotspots)
+	default:
+		return fmt.Errorf("unknown hotspots format %q", *format)
+	}
+	return nil
+}
@@ -6,33 +6,164 @@ import (
 	"log"
 	"os"
 	"os/exec"
+	"strings"
+	"time"
 
 	"github.com/gotech-hub/gocheck/analyzer"
+	"github.com/gotech-hub/gocheck/coverage"
+	"github.com/gotech-hub/gocheck/history"
+	"github.com/gotech-hub/gocheck/hotspot"
+	"github.com/gotech-hub/gocheck/metrics"
+	"github.com/gotech-hub/gocheck/owners"
 	"github.com/gotech-hub/gocheck/report"
 	"github.com/gotech-hub/gocheck/scanner"
+	"github.com/gotech-hub/gocheck/vcs"
 )
 
 const version = "gocheck v1.0.1"
 
+// reportFormats lists the values accepted by --format.
+var reportFormats = map[string]bool{
+	"markdown": true,
+	"github":   true,
+	"azure":    true,
+	"teamcity": true,
+	"csv":      true,
+}
+
+// ScanOptions chọn các báo cáo được sinh ra sau khi quét.
+type ScanOptions struct {
+	HTML             bool
+	JSON             bool
+	Formats          []string // các định dạng bổ sung, xem reportFormats
+	HistoryPath      string   // nếu khác rỗng, ghi tóm tắt lần chạy vào file lịch sử này
+	RepoURL          string   // mẫu link tới mã nguồn, dùng cho báo cáo Markdown
+	MarkdownMaxBytes int
+	TemplatePath     string          // template tùy chỉnh (text/template hoặc html/template)
+	TemplateOut      string          // file kết quả của template, mặc định suy ra từ tên template
+	Owner            string          // chỉ giữ các finding thuộc owner này (theo CODEOWNERS)
+	Blame            bool            // gắn tác giả, commit và ngày của dòng vi phạm bằng git blame
+	RecentDays       int             // finding mới hơn số ngày này được coi là mới xuất hiện
+	CSVPivot         bool            // ghi thêm bảng tổng hợp theo rule khi xuất CSV
+	Weights          report.Weights  // trọng số tính điểm maintainability theo severity/category
+	Hotspots         bool            // thêm biểu đồ hotspot (git churn × độ phức tạp) vào báo cáo HTML
+	HotspotsSince    string          // chỉ tính các commit sau thời điểm này khi đo churn
+	CoverProfile     string          // file coverage của go test -coverprofile
+	Analyzer         analyzer.Config // ngưỡng của các rule phân tích
+}
+
 // Scan quét mã nguồn Go trong path, sinh báo cáo HTML/JSON nếu được chọn.
-func Scan(path string, htmlOutput, jsonOutput bool) error {
+func Scan(path string, opts ScanOptions) error {
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		return fmt.Errorf("Invalid path: %s", path)
 	}
 
 	files := scanner.ScanDir(path)
-	results := analyzer.AnalyzeFiles(files)
+	results := analyzeFiles(path, files, opts.Analyzer)
+
+	codeOwners, err := owners.Find(path)
+	if err != nil {
+		return fmt.Errorf("Cannot read CODEOWNERS: %v", err)
+	}
+	if codeOwners != nil {
+		codeOwners.Annotate(results)
+	}
+	if opts.Owner != "" {
+		if codeOwners == nil {
+			return fmt.Errorf("--owner needs a CODEOWNERS file, none found for %s", path)
+		}
+		results = owners.Filter(results, opts.Owner)
+	}
+	if opts.Blame {
+		vcs.AnnotateBlame(results)
+	}
+	var profile *coverage.Profile
+	if opts.CoverProfile != "" {
+		profile, err = coverage.Load(opts.CoverProfile, path)
+		if err != nil {
+			return fmt.Errorf("Cannot read coverage profile: %v", err)
+		}
+		profile.Annotate(results)
+	}
+	meta := report.Metadata{
+		Version:    version,
+		Root:       path,
+		Commit:     vcs.Head(path),
+		RepoRoot:   vcs.TopLevel(path),
+		Lines:      scanner.CountLines(files),
+		RecentDays: opts.RecentDays,
+		Weights:    opts.Weights,
+		Coverage:   profile,
+		Docs:       metrics.DocCoverage(files),
+		Code:       metrics.FileCodeMetrics(files),
+	}
+	if opts.Hotspots {
+		hotspots, err := hotspot.Analyze(path, opts.HotspotsSince, files, results, false)
+		if err != nil {
+			return fmt.Errorf("Cannot read git history of %s: %v", path, err)
+		}
+		meta.Hotspots = hotspots
+	}
+
+	if opts.HistoryPath != "" {
+		runs, err := history.Load(opts.HistoryPath)
+		if err != nil {
+			return fmt.Errorf("Cannot read history %s: %v", opts.HistoryPath, err)
+		}
+		run := history.Summarize(results, meta.Commit, time.Now())
+		if err := history.Append(opts.HistoryPath, run); err != nil {
+			return fmt.Errorf("Cannot write history %s: %v", opts.HistoryPath, err)
+		}
+		meta.History = append(runs, run)
+		fmt.Printf("GoCheck: run recorded → %s\n", opts.HistoryPath)
+	}
 
-	if htmlOutput {
-		report.GenerateHTML(results)
+	if opts.HTML {
+		report.GenerateHTML(results, meta)
 		fmt.Println("GoCheck: HTML report generated → report.html")
 	}
 
-	if jsonOutput {
-		report.GenerateJSON(results)
+	if opts.JSON {
+		report.GenerateJSON(results, meta)
 		fmt.Println("GoCheck: JSON report generated → report.json")
 	}
 
+	for _, format := range opts.Formats {
+		switch format {
+		case "markdown":
+			report.GenerateMarkdown(results, meta, report.MarkdownOptions{RepoURL: opts.RepoURL, MaxBytes: opts.MarkdownMaxBytes})
+			fmt.Println("GoCheck: Markdown report generated → report.md")
+		case "csv":
+			if err := report.GenerateCSV(results, meta, opts.CSVPivot); err != nil {
+				return fmt.Errorf("Cannot write CSV report: %v", err)
+			}
+			fmt.Println("GoCheck: CSV report generated → report.csv")
+			if opts.CSVPivot {
+				fmt.Println("GoCheck: CSV rule summary generated → report-rules.csv")
+			}
+		case "github", "azure", "teamcity":
+			if err := report.WriteAnnotations(os.Stdout, format, results); err != nil {
+				return err
+			}
+		}
+	}
+
+	if opts.TemplatePath != "" {
+		out := opts.TemplateOut
+		if out == "" {
+			var err error
+			if out, err = report.TemplateOutput(opts.TemplatePath); err != nil {
+				return err
+			}
+		} else if report.SameFile(out, opts.TemplatePath) {
+			return fmt.Errorf("--template-out %s is the template itself", out)
+		}
+		if err := report.GenerateTemplate(results, meta, opts.TemplatePath, out); err != nil {
+			return fmt.Errorf("Cannot render template %s: %v", opts.TemplatePath, err)
+		}
+		fmt.Printf("GoCheck: template report generated → %s\n", out)
+	}
+
 	return nil
 }
 
@@ -41,31 +172,115 @@ func showHelp() {
 	fmt.Println("")
 	fmt.Println("Usage:")
 	fmt.Println("  gocheck [flags]")
+	fmt.Println("  gocheck history [list|compare <run> <run>] [--file path]")
+	fmt.Println("  gocheck diff [--format text|json|markdown|html] [--out file] old.json new.json")
+	fmt.Println("  gocheck metrics [--path dir] [--format text|json|csv] [--out file]")
+	fmt.Println("  gocheck hotspots [--path dir] [--since date] [--by file|func] [--top n] [--format text|json]")
 	fmt.Println("")
 	fmt.Println("Flags:")
 	fmt.Println("  --path string     Path to scan (default: .)")
 	fmt.Println("  --html            Generate HTML report (default: true)")
 	fmt.Println("  --json            Generate JSON report (default: true)")
+	fmt.Println("  --format string   Extra report formats, comma-separated: markdown, github, azure, teamcity, csv")
+	fmt.Println("  --csv-pivot       With --format csv, also write a per-rule summary to report-rules.csv")
+	fmt.Println("  --repo-url string Link pattern for locations in the Markdown report,")
+	fmt.Println("                    e.g. https://github.com/org/repo/blob/{commit}/{path}#L{line}")
+	fmt.Printf("  --markdown-max-bytes int  Truncate the Markdown report to this size (default: %d)\n", report.DefaultMarkdownMaxBytes)
+	fmt.Println("  --owner string    Only report findings owned by this CODEOWNERS owner (e.g. @org/team)")
+	fmt.Println("  --blame           Annotate findings with git blame author, commit and date")
+	fmt.Printf("  --recent-days int Findings younger than this are reported as new (default: %d)\n", report.DefaultRecentDays)
+	fmt.Println("  --score-weights string  Maintainability score weights, per severity, category, Complexity or Comments, e.g. Critical=20,Complexity=1")
+	fmt.Printf("  --max-cyclomatic int  Report functions with a higher cyclomatic complexity (default: %d)\n", analyzer.DefaultConfig().MaxCyclomatic)
+	fmt.Printf("  --max-cognitive int   Report functions with a higher cognitive complexity (default: %d)\n", analyzer.DefaultConfig().MaxCognitive)
+	fmt.Println("  --func-length string  How function length is measured: physical, logical or statements (default: logical)")
+	fmt.Printf("  --max-func-length int Report functions longer than this (default: %d)\n", analyzer.DefaultConfig().MaxFuncLength)
+	fmt.Println("  --coverprofile string  Go coverage profile (go test -coverprofile) to annotate findings with")
+	fmt.Println("  --hotspots        Add a churn/complexity hotspot chart to the HTML report (needs git)")
+	fmt.Printf("  --hotspots-since string  Only count commits after this date (default: %q)\n", vcs.DefaultChurnSince)
+	fmt.Println("  --template string Render findings with a custom text/template or html/template file")
+	fmt.Println("  --template-out string  Output file for --template (default: template name without .tmpl)")
 	fmt.Println("  --version         Show version information")
 	fmt.Println("  --help            Show this help message")
 	fmt.Println("  --verbose         Enable verbose output")
 	fmt.Println("  --stats           Show statistics after scanning")
+	fmt.Printf("  --history string  Record this run in a history file (e.g. %s)\n", history.DefaultPath)
 	fmt.Println("")
 	fmt.Println("Examples:")
 	fmt.Println("  gocheck --path ./myproject --html --json")
 	fmt.Println("  gocheck --path ./src --json=false --html=true --verbose")
+	fmt.Println("  gocheck --html=false --json=false --format markdown --repo-url 'https://github.com/org/repo/blob/{commit}/{path}#L{line}'")
+	fmt.Printf("  gocheck --history %s && gocheck history compare -2 -1\n", history.DefaultPath)
 }
 
-func main() {
-	// Check for required tools
-	missingTools := []string{}
-	if _, err := exec.LookPath("gosec"); err != nil {
-		missingTools = append(missingTools, "gosec")
+// requiredTools are the external analyzers run on every scanned file.
+var requiredTools = []string{"gosec", "staticcheck"}
+
+// missingTools returns the required tools that are not in PATH.
+func missingTools() []string {
+	var missing []string
+	for _, tool := range requiredTools {
+		if _, err := exec.LookPath(tool); err != nil {
+			missing = append(missing, tool)
+		}
 	}
-	if _, err := exec.LookPath("staticcheck"); err != nil {
-		missingTools = append(missingTools, "staticcheck")
+	return missing
+}
+
+// analyzerFlags registers the rule thresholds on fs, so the scan and the
+// subcommands that analyze code accept the same flags.
+func analyzerFlags(fs *flag.FlagSet) *analyzer.Config {
+	cfg := analyzer.DefaultConfig()
+	fs.IntVar(&cfg.MaxCyclomatic, "max-cyclomatic", cfg.MaxCyclomatic, "Report functions with a higher cyclomatic complexity")
+	fs.IntVar(&cfg.MaxCognitive, "max-cognitive", cfg.MaxCognitive, "Report functions with a higher cognitive complexity")
+	fs.StringVar(&cfg.FuncLength, "func-length", cfg.FuncLength, "How function length is measured: physical, logical or statements")
+	fs.IntVar(&cfg.MaxFuncLength, "max-func-length", cfg.MaxFuncLength, "Report functions longer than this")
+	return &cfg
+}
+
+// analyzeFiles runs the analyzers on the files found under path, with
+// fingerprints relative to the repository, so checkouts in other
+// directories (a tag worktree, a CI workspace) can be diffed.
+func analyzeFiles(path string, files []string, cfg analyzer.Config) []analyzer.Finding {
+	cfg.Root = vcs.TopLevel(path)
+	if cfg.Root == "" {
+		cfg.Root = path
 	}
-	if len(missingTools) > 0 {
+	return analyzer.AnalyzeFilesWithConfig(files, cfg)
+}
+
+func main() {
+	// Subcommands do not scan, so they do not need the external tools
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "history":
+			if err := runHistory(os.Args[2:]); err != nil {
+				fmt.Println("❌ Error:", err)
+				os.Exit(1)
+			}
+			return
+		case "diff":
+			if err := runDiff(os.Args[2:]); err != nil {
+				fmt.Println("❌ Error:", err)
+				os.Exit(1)
+			}
+			return
+		case "metrics":
+			if err := runMetrics(os.Args[2:]); err != nil {
+				fmt.Println("❌ Error:", err)
+				os.Exit(1)
+			}
+			return
+		case "hotspots":
+			if err := runHotspots(os.Args[2:]); err != nil {
+				fmt.Println("❌ Error:", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	// Check for required tools
+	if missingTools := missingTools(); len(missingTools) > 0 {
 		fmt.Printf("\u274c Error: The following required tools are missing: %v\n", missingTools)
 		fmt.Println("Please install them as described in the README before running gocheck.")
 		os.Exit(1)
@@ -79,7 +294,22 @@ func main() {
 		help    = flag.Bool("help", false, "Show help information")
 		verbose = flag.Bool("verbose", false, "Enable verbose output")
 		stats   = flag.Bool("stats", false, "Show statistics after scanning")
+		hist    = flag.String("history", "", "Record this run in a history file")
+		formats = flag.String("format", "", "Extra report formats, comma-separated: markdown, github, azure, teamcity, csv")
+		pivot   = flag.Bool("csv-pivot", false, "With --format csv, also write a per-rule summary")
+		repoURL = flag.String("repo-url", "", "Link pattern for locations in the Markdown report")
+		mdMax   = flag.Int("markdown-max-bytes", report.DefaultMarkdownMaxBytes, "Truncate the Markdown report to this size")
+		tmpl    = flag.String("template", "", "Render findings with a custom template file")
+		tmplOut = flag.String("template-out", "", "Output file for --template")
+		owner   = flag.String("owner", "", "Only report findings owned by this CODEOWNERS owner")
+		blame   = flag.Bool("blame", false, "Annotate findings with git blame information")
+		recent  = flag.Int("recent-days", report.DefaultRecentDays, "Findings younger than this many days are new")
+		cover   = flag.String("coverprofile", "", "Go coverage profile to annotate findings with")
+		hspots  = flag.Bool("hotspots", false, "Add a churn/complexity hotspot chart to the HTML report")
+		hsSince = flag.String("hotspots-since", vcs.DefaultChurnSince, "Only count commits after this date for --hotspots")
+		weights = flag.String("score-weights", "", "Maintainability score weights per severity, category, Complexity or Comments, e.g. Critical=20,Security=2")
 	)
+	analyzerCfg := analyzerFlags(flag.CommandLine)
 
 	flag.Parse()
 
@@ -98,8 +328,29 @@ func main() {
 		log.Fatal("❌ Error: --path flag is required")
 	}
 
-	if !*html && !*json {
-		log.Fatal("❌ Error: At least one of --html or --json must be true")
+	var extraFormats []string
+	for _, format := range strings.Split(*formats, ",") {
+		format = strings.TrimSpace(format)
+		if format == "" {
+			continue
+		}
+		if !reportFormats[format] {
+			log.Fatalf("❌ Error: Unknown report format %q", format)
+		}
+		extraFormats = append(extraFormats, format)
+	}
+
+	if err := analyzerCfg.Validate(); err != nil {
+		log.Fatalf("❌ Error: --func-length: %v", err)
+	}
+
+	scoreWeights, err := report.ParseWeights(*weights)
+	if err != nil {
+		log.Fatalf("❌ Error: --score-weights: %v", err)
+	}
+
+	if !*html && !*json && len(extraFormats) == 0 && *tmpl == "" {
+		log.Fatal("❌ Error: At least one of --html, --json, --format or --template must be set")
 	}
 
 	if *verbose {
@@ -108,7 +359,25 @@ func main() {
 		fmt.Printf("  JSON report: %v\n", *json)
 	}
 
-	err := Scan(*path, *html, *json)
+	err = Scan(*path, ScanOptions{
+		HTML:             *html,
+		JSON:             *json,
+		Formats:          extraFormats,
+		HistoryPath:      *hist,
+		RepoURL:          *repoURL,
+		MarkdownMaxBytes: *mdMax,
+		TemplatePath:     *tmpl,
+		TemplateOut:      *tmplOut,
+		Owner:            *owner,
+		Blame:            *blame,
+		RecentDays:       *recent,
+		CSVPivot:         *pivot,
+		Weights:          scoreWeights,
+		Hotspots:         *hspots,
+		HotspotsSince:    *hsSince,
+		CoverProfile:     *cover,
+		Analyzer:         *analyzerCfg,
+	})
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -0,0 +1,315 @@
+package metrics
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// Contribution is one construct adding to the complexity of a function.
+type Contribution struct {
+	Line      int
+	Construct string // "if", "for", "case", "&&", …
+	Increment int    // 1 for cyclomatic complexity, 1 + nesting for most cognitive increments
+}
+
+// Cyclomatic returns the McCabe cyclomatic complexity of a function body:
+// one plus the number of decision points (if, for, range, non-default case
+// and select clauses, && and ||). Function literals inside body are not
+// included, they are functions of their own.
+func Cyclomatic(body *ast.BlockStmt) int {
+	n, _ := CyclomaticDetail(nil, body)
+	return n
+}
+
+// CyclomaticDetail is Cyclomatic returning the contributing constructs too.
+// fset may be nil, the contributions then have no line numbers.
+func CyclomaticDetail(fset *token.FileSet, body *ast.BlockStmt) (int, []Contribution) {
+	if body == nil {
+		return 1, nil
+	}
+	var parts []Contribution
+	add := func(pos token.Pos, construct string) {
+		parts = append(parts, Contribution{Line: line(fset, pos), Construct: construct, Increment: 1})
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.IfStmt:
+			add(n.Pos(), "if")
+		case *ast.ForStmt:
+			add(n.Pos(), "for")
+		case *ast.RangeStmt:
+			add(n.Pos(), "range")
+		case *ast.CaseClause:
+			if n.List != nil {
+				add(n.Pos(), "case")
+			}
+		case *ast.CommClause:
+			if n.Comm != nil {
+				add(n.Pos(), "select case")
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				add(n.OpPos, n.Op.String())
+			}
+		}
+		return true
+	})
+	return 1 + len(parts), parts
+}
+
+// Cognitive returns the cognitive complexity of a function body as defined
+// by SonarSource: breaks in the linear flow (if, else, switch, select, loops,
+// labelled jumps, sequences of mixed && and ||, recursion) add one, and
+// structures nested inside other structures add their nesting level on top.
+// name is the function's own name as a recursive call spells it, "walk" for
+// a function and "s.walk" for a method with receiver s, used to detect
+// recursion; it may be empty.
+func Cognitive(fset *token.FileSet, name string, body *ast.BlockStmt) (int, []Contribution) {
+	if body == nil {
+		return 0, nil
+	}
+	c := &cognitive{fset: fset, name: name}
+	c.walk(body, 0)
+	total := 0
+	for _, p := range c.parts {
+		total += p.Increment
+	}
+	return total, c.parts
+}
+
+type cognitive struct {
+	fset  *token.FileSet
+	name  string
+	parts []Contribution
+}
+
+func (c *cognitive) add(pos token.Pos, construct string, increment int) {
+	c.parts = append(c.parts, Contribution{Line: line(c.fset, pos), Construct: construct, Increment: increment})
+}
+
+// walk visits n at the given nesting level.
+func (c *cognitive) walk(n ast.Node, nesting int) {
+	if n == nil {
+		return
+	}
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.IfStmt:
+			c.add(n.Pos(), "if", 1+nesting)
+			c.walkIf(n, nesting)
+			return false
+		case *ast.ForStmt:
+			c.add(n.Pos(), "for", 1+nesting)
+			c.walkAll(nesting, n.Init, n.Cond, n.Post)
+			c.walk(n.Body, nesting+1)
+			return false
+		case *ast.RangeStmt:
+			c.add(n.Pos(), "range", 1+nesting)
+			c.walk(n.X, nesting)
+			c.walk(n.Body, nesting+1)
+			return false
+		case *ast.SwitchStmt:
+			c.add(n.Pos(), "switch", 1+nesting)
+			c.walkAll(nesting, n.Init, n.Tag)
+			c.walk(n.Body, nesting+1)
+			return false
+		case *ast.TypeSwitchStmt:
+			c.add(n.Pos(), "switch", 1+nesting)
+			c.walkAll(nesting, n.Init, n.Assign)
+			c.walk(n.Body, nesting+1)
+			return false
+		case *ast.SelectStmt:
+			c.add(n.Pos(), "select", 1+nesting)
+			c.walk(n.Body, nesting+1)
+			return false
+		case *ast.BranchStmt:
+			if n.Label != nil {
+				c.add(n.Pos(), n.Tok.String()+" "+n.Label.Name, 1)
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				c.walkLogical(n, nesting)
+				return false
+			}
+		case *ast.CallExpr:
+			if c.name != "" && callee(n.Fun) == c.name {
+				c.add(n.Pos(), "recursion", 1)
+			}
+		}
+		return true
+	})
+}
+
+// callee returns the called name of a plain call, "f", or of a call through
+// a variable, "s.walk", and "" for other calls.
+func callee(fun ast.Expr) string {
+	switch fun := fun.(type) {
+	case *ast.Ident:
+		return fun.Name
+	case *ast.SelectorExpr:
+		if x, ok := fun.X.(*ast.Ident); ok {
+			return x.Name + "." + fun.Sel.Name
+		}
+	}
+	return ""
+}
+
+func (c *cognitive) walkAll(nesting int, nodes ...ast.Node) {
+	for _, n := range nodes {
+		c.walk(n, nesting)
+	}
+}
+
+// walkIf visits the parts of an if statement whose own increment has
+// already been added. "else if" and "else" add one without a nesting
+// increment, their bodies are nested like the if body.
+func (c *cognitive) walkIf(n *ast.IfStmt, nesting int) {
+	c.walkAll(nesting, n.Init, n.Cond)
+	c.walk(n.Body, nesting+1)
+	switch e := n.Else.(type) {
+	case *ast.IfStmt:
+		c.add(e.Pos(), "else if", 1)
+		c.walkIf(e, nesting)
+	case *ast.BlockStmt:
+		c.add(e.Pos(), "else", 1)
+		c.walk(e, nesting+1)
+	}
+}
+
+// walkLogical adds one for every sequence of like logical operators in a
+// condition, so "a && b && c" adds one and "a && b || c" adds two.
+func (c *cognitive) walkLogical(n *ast.BinaryExpr, nesting int) {
+	var ops []*ast.BinaryExpr
+	var operands []ast.Expr
+	var flatten func(e ast.Expr)
+	flatten = func(e ast.Expr) {
+		if p, ok := e.(*ast.ParenExpr); ok {
+			e = p.X
+		}
+		if b, ok := e.(*ast.BinaryExpr); ok && (b.Op == token.LAND || b.Op == token.LOR) {
+			flatten(b.X)
+			ops = append(ops, b)
+			flatten(b.Y)
+			return
+		}
+		operands = append(operands, e)
+	}
+	flatten(n)
+	for i, op := range ops {
+		if i == 0 || op.Op != ops[i-1].Op {
+			c.add(op.OpPos, op.Op.String(), 1)
+		}
+	}
+	for _, e := range operands {
+		c.walk(e, nesting)
+	}
+}
+
+func line(fset *token.FileSet, pos token.Pos) int {
+	if fset == nil {
+		return 0
+	}
+	return fset.Position(pos).Line
+}
+
+// Describe summarizes contributions for a finding, grouped by construct in
+// order of first appearance, e.g. "if +5 (lines 12, 18, 30), && +1 (line 14)".
+func Describe(parts []Contribution) string {
+	var order []string
+	sums := map[string]int{}
+	lines := map[string][]string{}
+	for _, p := range parts {
+		if _, ok := sums[p.Construct]; !ok {
+			order = append(order, p.Construct)
+		}
+		sums[p.Construct] += p.Increment
+		lines[p.Construct] = append(lines[p.Construct], fmt.Sprint(p.Line))
+	}
+	var groups []string
+	for _, construct := range order {
+		word := "line"
+		if len(lines[construct]) > 1 {
+			word = "lines"
+		}
+		groups = append(groups, fmt.Sprintf("%s +%d (%s %s)", construct, sums[construct], word, strings.Join(lines[construct], ", ")))
+	}
+	return strings.Join(groups, ", ")
+}
+
+// Nesting returns the deepest nesting of control structures in a function
+// body and where it is reached. if, for, range, switch, select and function
+// literals each open a level; the body itself is level 0 and an "else if"
+// stays on the level of its if.
+func Nesting(body *ast.BlockStmt) (int, token.Pos) {
+	var maxDepth int
+	var deepest token.Pos
+	var walk func(n ast.Node, depth int)
+	enter := func(pos token.Pos, depth int) {
+		if depth > maxDepth {
+			maxDepth, deepest = depth, pos
+		}
+	}
+	var walkIf func(n *ast.IfStmt, depth int)
+	walkIf = func(n *ast.IfStmt, depth int) {
+		enter(n.Pos(), depth+1)
+		walk(n.Init, depth)
+		walk(n.Cond, depth)
+		walk(n.Body, depth+1)
+		switch e := n.Else.(type) {
+		case *ast.IfStmt:
+			walkIf(e, depth)
+		case *ast.BlockStmt:
+			walk(e, depth+1)
+		}
+	}
+	walk = func(n ast.Node, depth int) {
+		if n == nil {
+			return
+		}
+		ast.Inspect(n, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.IfStmt:
+				walkIf(n, depth)
+				return false
+			case *ast.ForStmt:
+				enter(n.Pos(), depth+1)
+				walk(n.Body, depth+1)
+				return false
+			case *ast.RangeStmt:
+				enter(n.Pos(), depth+1)
+				walk(n.X, depth)
+				walk(n.Body, depth+1)
+				return false
+			case *ast.SwitchStmt:
+				enter(n.Pos(), depth+1)
+				walk(n.Init, depth)
+				walk(n.Tag, depth)
+				walk(n.Body, depth+1)
+				return false
+			case *ast.TypeSwitchStmt:
+				enter(n.Pos(), depth+1)
+				walk(n.Body, depth+1)
+				return false
+			case *ast.SelectStmt:
+				enter(n.Pos(), depth+1)
+				walk(n.Body, depth+1)
+				return false
+			case *ast.FuncLit:
+				enter(n.Pos(), depth+1)
+				walk(n.Body, depth+1)
+				return false
+			}
+			return true
+		})
+	}
+	if body != nil {
+		walk(body, 0)
+	}
+	return maxDepth, deepest
+}
@@ -0,0 +1,189 @@
+package metrics
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+// parseBody parses the body of func f in a file made of src.
+func parseBody(t *testing.T, src string) (*token.FileSet, *ast.BlockStmt) {
+	t.Helper()
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, "x.go", "package x\n\n"+src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, decl := range node.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == "f" {
+			return fset, fn.Body
+		}
+	}
+	t.Fatal("no func f")
+	return nil, nil
+}
+
+func TestCognitive(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want int
+	}{
+		{"empty", `func f() {}`, 0},
+		{"if", `func f(a bool) {
+	if a {
+	}
+}`, 1},
+		{"else if and else add one each", `func f(a, b bool) {
+	if a {
+	} else if b {
+	} else {
+	}
+}`, 3},
+		{"nesting adds its level", `func f(n int) {
+	for i := 0; i < n; i++ {
+		if i > 2 {
+		}
+	}
+}`, 3},
+		{"sum of primes", `func f(max int) int {
+	total := 0
+OUT:
+	for i := 2; i <= max; i++ {
+		for j := 2; j < i; j++ {
+			if i%j == 0 {
+				continue OUT
+			}
+		}
+		total += i
+	}
+	return total
+}`, 7},
+		{"switch counts once", `func f(n int) string {
+	switch n {
+	case 1:
+		return "one"
+	case 2:
+		return "two"
+	default:
+		return "many"
+	}
+}`, 1},
+		{"like operators add one", `func f(a, b, c bool) bool {
+	return a && b && c
+}`, 1},
+		{"mixed operators add one per sequence", `func f(a, b, c bool) bool {
+	return a && b || c
+}`, 2},
+		{"condition of an if", `func f(a, b bool) {
+	if a && b {
+	}
+}`, 2},
+		{"recursion", `func f(n int) int {
+	return f(n - 1)
+}`, 1},
+		{"method recursion", `func (s *S) f(n int) int {
+	if n == 0 {
+		return t.f(n)
+	}
+	return s.f(n - 1)
+}`, 2},
+		{"function literals are functions of their own", `func f(a bool) {
+	go func() {
+		if a {
+		}
+	}()
+}`, 0},
+		{"switch in a loop", `func f(xs []int) {
+	for _, x := range xs {
+		switch {
+		case x > 0:
+			if x > 10 {
+			}
+		}
+	}
+}`, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset, body := parseBody(t, tt.src)
+			self := "f"
+			if strings.HasPrefix(tt.src, "func (s ") {
+				self = "s.f"
+			}
+			got, parts := Cognitive(fset, self, body)
+			if got != tt.want {
+				t.Errorf("Cognitive = %d, want %d (%s)", got, tt.want, Describe(parts))
+			}
+		})
+	}
+}
+
+func TestNesting(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		want     int
+		wantLine int // line of the deepest structure, the func is on line 3
+	}{
+		{"flat", `func f() {
+	println()
+}`, 0, 0},
+		{"if", `func f(a bool) {
+	if a {
+	}
+}`, 1, 4},
+		{"if in a loop", `func f(xs []int) {
+	for range xs {
+		if len(xs) > 1 {
+		}
+	}
+}`, 2, 5},
+		{"else if stays on the level of its if", `func f(a, b bool) {
+	if a {
+	} else if b {
+		if a {
+		}
+	}
+}`, 2, 6},
+		{"else body is nested", `func f(a bool) {
+	if a {
+	} else {
+		for {
+		}
+	}
+}`, 2, 6},
+		{"function literal opens a level", `func f(a bool) {
+	go func() {
+		if a {
+		}
+	}()
+}`, 2, 5},
+		{"case bodies", `func f(n int) {
+	switch n {
+	case 1:
+		for {
+			select {}
+		}
+	}
+}`, 3, 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset, body := parseBody(t, tt.src)
+			got, pos := Nesting(body)
+			if got != tt.want {
+				t.Errorf("Nesting = %d, want %d", got, tt.want)
+			}
+			deepest := 0
+			if pos.IsValid() {
+				deepest = line(fset, pos)
+			}
+			if deepest != tt.wantLine {
+				t.Errorf("deepest at line %d, want %d", deepest, tt.wantLine)
+			}
+		})
+	}
+}
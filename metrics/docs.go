@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+// Decl is an exported declaration, which should carry a doc comment.
+type Decl struct {
+	Name  string // "Name", or "Type.Method" for methods
+	Kind  string // "function", "method", "type", "const" or "var"
+	Ident *ast.Ident
+	Doc   *ast.CommentGroup // nil when undocumented
+	Group bool              // Doc is the comment of a const/var/type group, not of this name alone
+}
+
+// ExportedDecls returns the exported package-level declarations of f and
+// the methods of its exported types. A name in a documented group counts
+// as documented.
+func ExportedDecls(f *ast.File) []Decl {
+	var decls []Decl
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if !d.Name.IsExported() {
+				continue
+			}
+			if d.Recv == nil {
+				decls = append(decls, Decl{Name: d.Name.Name, Kind: "function", Ident: d.Name, Doc: d.Doc})
+				continue
+			}
+			name := FuncName(d)
+			if typ, _, _ := strings.Cut(name, "."); ast.IsExported(typ) {
+				decls = append(decls, Decl{Name: name, Kind: "method", Ident: d.Name, Doc: d.Doc})
+			}
+		case *ast.GenDecl:
+			kind := d.Tok.String()
+			for _, spec := range d.Specs {
+				var doc *ast.CommentGroup
+				var names []*ast.Ident
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					doc, names = s.Doc, []*ast.Ident{s.Name}
+				case *ast.ValueSpec:
+					doc, names = s.Doc, s.Names
+				}
+				group := false
+				if doc == nil && d.Doc != nil {
+					doc, group = d.Doc, d.Lparen.IsValid()
+				}
+				for _, id := range names {
+					if id.IsExported() {
+						decls = append(decls, Decl{Name: id.Name, Kind: kind, Ident: id, Doc: doc, Group: group})
+					}
+				}
+			}
+		}
+	}
+	return decls
+}
+
+// DocCount counts the exported declarations of a file or package and how
+// many of them have a doc comment.
+type DocCount struct {
+	Exported   int `json:"exported"`
+	Documented int `json:"documented"`
+}
+
+// Add returns the sum of c and o.
+func (c DocCount) Add(o DocCount) DocCount {
+	return DocCount{Exported: c.Exported + o.Exported, Documented: c.Documented + o.Documented}
+}
+
+// Percent returns the share of documented declarations, 100 when nothing
+// is exported.
+func (c DocCount) Percent() float64 {
+	if c.Exported == 0 {
+		return 100
+	}
+	return float64(c.Documented) * 100 / float64(c.Exported)
+}
+
+// DocsRequired reports whether the exported names of f need doc comments.
+// Test files, generated files and package main, which is not imported, are
+// exempt; the missing-doc rule and DocCoverage agree on this.
+func DocsRequired(path string, f *ast.File) bool {
+	return !strings.HasSuffix(path, "_test.go") && !ast.IsGenerated(f) && f.Name.Name != "main"
+}
+
+// countDocs counts the exported declarations of f.
+func countDocs(f *ast.File) DocCount {
+	var c DocCount
+	for _, d := range ExportedDecls(f) {
+		c.Exported++
+		if d.Doc != nil {
+			c.Documented++
+		}
+	}
+	return c
+}
+
+// DocCoverage counts the documented exported declarations of each file.
+// Files whose docs are not required (see DocsRequired) and files that
+// cannot be parsed are left out of the result.
+func DocCoverage(files []string) map[string]DocCount {
+	docs := make(map[string]DocCount, len(files))
+	fset := token.NewFileSet()
+	for _, file := range files {
+		f, err := parser.ParseFile(fset, file, nil, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil || !DocsRequired(file, f) {
+			continue
+		}
+		docs[file] = countDocs(f)
+	}
+	return docs
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+)
+
+// Func holds the metrics of one function or method declaration.
+type Func struct {
+	Name       string `json:"name"` // "Name" or "Type.Method"
+	Line       int    `json:"line"`
+	EndLine    int    `json:"end_line"`
+	Complexity int    `json:"complexity"` // cyclomatic complexity
+}
+
+// FileFuncs parses file and returns the metrics of its function
+// declarations in source order.
+func FileFuncs(file string) ([]Func, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	var funcs []Func
+	for _, decl := range f.Decls {
+		d, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		funcs = append(funcs, Func{
+			Name:       FuncName(d),
+			Line:       fset.Position(d.Pos()).Line,
+			EndLine:    fset.Position(d.End()).Line,
+			Complexity: Cyclomatic(d.Body),
+		})
+	}
+	return funcs, nil
+}
+
+// FuncName returns "Name" for functions and "Type.Method" for methods, the
+// name findings and metrics use for a declaration.
+func FuncName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	t := fn.Recv.List[0].Type
+	for {
+		switch x := t.(type) {
+		case *ast.ParenExpr: // func (r (T)) and func (r (*T)) are valid
+			t = x.X
+			continue
+		case *ast.StarExpr:
+			t = x.X
+			continue
+		case *ast.IndexExpr:
+			t = x.X
+		case *ast.IndexListExpr:
+			t = x.X
+		}
+		break
+	}
+	if ident, ok := t.(*ast.Ident); ok {
+		return ident.Name + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
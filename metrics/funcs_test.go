@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func TestFuncName(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"func F() {}", "F"},
+		{"func (t T) M() {}", "T.M"},
+		{"func (t *T) M() {}", "T.M"},
+		{"func (t (T)) M() {}", "T.M"},
+		{"func (t (*T)) M() {}", "T.M"},
+		{"func (t *(T)) M() {}", "T.M"},
+		{"func (t G[K]) M() {}", "G.M"},
+		{"func (t *G[K, V]) M() {}", "G.M"},
+	}
+	for _, tt := range tests {
+		node, err := parser.ParseFile(token.NewFileSet(), "x.go", "package x\n"+tt.src, 0)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.src, err)
+		}
+		fn := node.Decls[0].(*ast.FuncDecl)
+		if got := FuncName(fn); got != tt.want {
+			t.Errorf("FuncName(%s) = %q, want %q", tt.src, got, tt.want)
+		}
+	}
+}
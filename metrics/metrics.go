@@ -0,0 +1,279 @@
+package metrics
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	gcscanner "github.com/gotech-hub/gocheck/scanner"
+)
+
+// Package holds the code metrics of one package directory. Counts other than
+// the test lines only cover non-test files.
+type Package struct {
+	Path          string  `json:"path"`        // directory relative to the scanned root
+	ImportPath    string  `json:"import_path"` // empty when the module path is unknown
+	Files         int     `json:"files"`
+	TestFiles     int     `json:"test_files"`
+	PhysicalLines int     `json:"physical_lines"`
+	LogicalLines  int     `json:"logical_lines"` // lines holding code, not blank or comment-only
+	CommentLines  int     `json:"comment_lines"`
+	CommentRatio  float64 `json:"comment_ratio"` // comment lines per physical line
+	Funcs         int     `json:"funcs"`         // functions and methods
+	Types         int     `json:"types"`
+	Interfaces    int     `json:"interfaces"`
+	AvgComplexity float64 `json:"avg_complexity"`
+	MaxComplexity int     `json:"max_complexity"`
+	MaxFunc       string  `json:"max_func,omitempty"` // function with the highest complexity
+	FanIn         int     `json:"fan_in"`             // packages of the module importing this one
+	FanOut        int     `json:"fan_out"`            // packages of the module imported by this one
+	Imports       int     `json:"imports"`            // every imported package, standard library included
+	TestLines     int     `json:"test_lines"`         // logical lines in _test.go files
+	TestRatio     float64 `json:"test_ratio"`         // test logical lines per code logical line
+	Exported      int     `json:"exported"`           // exported declarations, see ExportedDecls
+	Documented    int     `json:"documented"`         // exported declarations with a doc comment
+	DocCoverage   float64 `json:"doc_coverage"`       // documented per exported declaration, in percent
+
+	complexity int
+	imports    map[string]bool
+}
+
+// Collect computes the metrics of every package below root.
+func Collect(root string) ([]Package, error) {
+	modRoot, modPath := gcscanner.FindModule(root)
+	pkgs := map[string]*Package{}
+	fset := token.NewFileSet()
+	for _, file := range gcscanner.ScanDir(root) {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		f, err := parser.ParseFile(fset, file, src, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+		dir := filepath.Dir(file)
+		rel, err := filepath.Rel(root, dir)
+		if err != nil {
+			rel = dir
+		}
+		rel = filepath.ToSlash(rel)
+		p, ok := pkgs[rel]
+		if !ok {
+			p = &Package{Path: rel, imports: map[string]bool{}}
+			if modPath != "" {
+				p.ImportPath = importPath(modRoot, modPath, dir)
+			}
+			pkgs[rel] = p
+		}
+
+		code, comments := countLines(fset, f, src)
+		if strings.HasSuffix(file, "_test.go") {
+			p.TestFiles++
+			p.TestLines += code
+			continue
+		}
+		p.Files++
+		p.PhysicalLines += physicalLines(src)
+		p.LogicalLines += code
+		p.CommentLines += comments
+		if DocsRequired(file, f) {
+			docs := countDocs(f)
+			p.Exported += docs.Exported
+			p.Documented += docs.Documented
+		}
+		for _, imp := range f.Imports {
+			path, _ := strconv.Unquote(imp.Path.Value)
+			p.imports[path] = true
+		}
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				p.Funcs++
+				c := Cyclomatic(d.Body)
+				p.complexity += c
+				if c > p.MaxComplexity {
+					p.MaxComplexity = c
+					p.MaxFunc = FuncName(d)
+				}
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					if ts, ok := spec.(*ast.TypeSpec); ok {
+						p.Types++
+						if _, ok := ts.Type.(*ast.InterfaceType); ok {
+							p.Interfaces++
+						}
+					}
+				}
+			}
+		}
+	}
+
+	byImport := map[string]*Package{}
+	for _, p := range pkgs {
+		if p.ImportPath != "" {
+			byImport[p.ImportPath] = p
+		}
+	}
+	var result []Package
+	for _, p := range pkgs {
+		p.Imports = len(p.imports)
+		for imp := range p.imports {
+			if dep, ok := byImport[imp]; ok && dep != p {
+				p.FanOut++
+				dep.FanIn++
+			}
+		}
+	}
+	for _, p := range pkgs {
+		if p.Funcs > 0 {
+			p.AvgComplexity = float64(p.complexity) / float64(p.Funcs)
+		}
+		if p.PhysicalLines > 0 {
+			p.CommentRatio = float64(p.CommentLines) / float64(p.PhysicalLines)
+		}
+		if p.LogicalLines > 0 {
+			p.TestRatio = float64(p.TestLines) / float64(p.LogicalLines)
+		}
+		p.DocCoverage = DocCount{p.Exported, p.Documented}.Percent()
+		result = append(result, *p)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Path < result[j].Path })
+	return result, nil
+}
+
+// FileCode holds the code metrics of one file that the maintainability
+// score uses.
+type FileCode struct {
+	CommentLines int
+	Complexity   []int // cyclomatic complexity of each function and method
+}
+
+// FileCodeMetrics computes the code metrics of each file. Test files and
+// files that cannot be parsed are left out of the result.
+func FileCodeMetrics(files []string) map[string]FileCode {
+	code := make(map[string]FileCode, len(files))
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		src, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, src, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		_, comments := countLines(fset, f, src)
+		c := FileCode{CommentLines: comments}
+		for _, decl := range f.Decls {
+			if d, ok := decl.(*ast.FuncDecl); ok {
+				c.Complexity = append(c.Complexity, Cyclomatic(d.Body))
+			}
+		}
+		code[file] = c
+	}
+	return code
+}
+
+// Total sums the metrics of all packages into one row for the whole project.
+func Total(pkgs []Package) Package {
+	t := Package{Path: "total"}
+	for _, p := range pkgs {
+		t.Files += p.Files
+		t.TestFiles += p.TestFiles
+		t.PhysicalLines += p.PhysicalLines
+		t.LogicalLines += p.LogicalLines
+		t.CommentLines += p.CommentLines
+		t.Funcs += p.Funcs
+		t.Types += p.Types
+		t.Interfaces += p.Interfaces
+		t.TestLines += p.TestLines
+		t.Exported += p.Exported
+		t.Documented += p.Documented
+		t.complexity += p.complexity
+		if p.MaxComplexity > t.MaxComplexity {
+			t.MaxComplexity = p.MaxComplexity
+			t.MaxFunc = p.Path + "." + p.MaxFunc
+		}
+	}
+	if t.Funcs > 0 {
+		t.AvgComplexity = float64(t.complexity) / float64(t.Funcs)
+	}
+	if t.PhysicalLines > 0 {
+		t.CommentRatio = float64(t.CommentLines) / float64(t.PhysicalLines)
+	}
+	if t.LogicalLines > 0 {
+		t.TestRatio = float64(t.TestLines) / float64(t.LogicalLines)
+	}
+	t.DocCoverage = DocCount{t.Exported, t.Documented}.Percent()
+	return t
+}
+
+// CodeLines returns the set of lines of a parsed file that hold code, that
+// is anything but blanks and comments. tf is the file's token.File and src
+// its content.
+func CodeLines(tf *token.File, src []byte) map[int]bool {
+	var s scanner.Scanner
+	s.Init(tf, src, nil, 0)
+	lines := map[int]bool{}
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			return lines
+		}
+		// automatically inserted semicolons are not code
+		if tok == token.SEMICOLON && lit == "\n" {
+			continue
+		}
+		start := tf.Line(pos)
+		end := start + strings.Count(lit, "\n")
+		for line := start; line <= end; line++ {
+			lines[line] = true
+		}
+	}
+}
+
+// countLines returns the number of lines holding code and the number of
+// lines holding comments. A line with both counts in both.
+func countLines(fset *token.FileSet, f *ast.File, src []byte) (code, comments int) {
+	codeLines := CodeLines(fset.File(f.Pos()), src)
+	commentLines := map[int]bool{}
+	for _, g := range f.Comments {
+		for line := fset.Position(g.Pos()).Line; line <= fset.Position(g.End()).Line; line++ {
+			commentLines[line] = true
+		}
+	}
+	return len(codeLines), len(commentLines)
+}
+
+func physicalLines(src []byte) int {
+	n := strings.Count(string(src), "\n")
+	if len(src) > 0 && src[len(src)-1] != '\n' {
+		n++
+	}
+	return n
+}
+
+func importPath(modRoot, modPath, dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(modRoot, abs)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	if rel == "." {
+		return modPath
+	}
+	return modPath + "/" + filepath.ToSlash(rel)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gotech-hub/gocheck/metrics"
+	"github.com/gotech-hub/gocheck/report"
+)
+
+// runMetrics implements `gocheck metrics`, which prints code metrics per
+// package without running the analyzers.
+func runMetrics(args []string) error {
+	fs := flag.NewFlagSet("metrics", flag.ExitOnError)
+	path := fs.String("path", ".", "Path to scan")
+	format := fs.String("format", "text", "Output format: text, json or csv")
+	out := fs.String("out", "", "Write the metrics to this file instead of stdout")
+	fs.Usage = func() {
+		fmt.Println("Usage:")
+		fmt.Println("  gocheck metrics [--path dir] [--format text|json|csv] [--out file]")
+	}
+	fs.Parse(args)
+
+	if _, err := os.Stat(*path); err != nil {
+		return fmt.Errorf("Invalid path: %s", *path)
+	}
+	pkgs, err := metrics.Collect(*path)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch *format {
+	case "text":
+		report.WriteMetricsText(w, pkgs)
+	case "json":
+		return report.WriteMetricsJSON(w, pkgs)
+	case "csv":
+		return report.WriteMetricsCSV(w, pkgs)
+	default:
+		return fmt.Errorf("unknown metrics format %q", *format)
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package owners
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/gotech-hub/gocheck/analyzer"
+	"github.com/gotech-hub/gocheck/vcs"
+)
+
+// locations are where GitHub and GitLab look for a CODEOWNERS file, relative
+// to the repository root.
+var locations = []string{"CODEOWNERS", ".github/CODEOWNERS", "docs/CODEOWNERS", ".gitlab/CODEOWNERS"}
+
+type rule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// Owners resolves file paths to their owners using the rules of a
+// CODEOWNERS file.
+type Owners struct {
+	Base  string // directory the CODEOWNERS patterns are relative to
+	rules []rule
+}
+
+// Find looks for a CODEOWNERS file in dir and its parent directories up to
+// the top level of the git repository containing dir, and loads the first
+// one found. Outside a git repository only dir itself is searched, so a
+// CODEOWNERS file of an enclosing directory, such as a parent monorepo or
+// the home directory, is never used. It returns nil, nil if there is none.
+func Find(dir string) (*Owners, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	top := vcs.TopLevel(dir)
+	if top == "" {
+		top = dir
+	}
+	top = realPath(top)
+	for {
+		for _, loc := range locations {
+			path := filepath.Join(dir, loc)
+			if _, err := os.Stat(path); err == nil {
+				return Load(path, dir)
+			}
+		}
+		parent := filepath.Dir(dir)
+		if realPath(dir) == top || parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// realPath resolves symbolic links in path, as git does for the top level.
+func realPath(path string) string {
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		return real
+	}
+	return path
+}
+
+// Load parses the CODEOWNERS file at path, whose patterns are relative to
+// base.
+func Load(path, base string) (*Owners, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	o := &Owners{Base: base}
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(stripComment(line))
+		// GitLab sections ("[Section]") only group rules, they own nothing
+		if strings.HasPrefix(fields[0], "[") || strings.HasPrefix(fields[0], "^[") {
+			continue
+		}
+		re, err := compilePattern(fields[0])
+		if err != nil {
+			continue
+		}
+		o.rules = append(o.rules, rule{pattern: re, owners: fields[1:]})
+	}
+	return o, sc.Err()
+}
+
+// stripComment removes a trailing comment, a # after white space, from a
+// CODEOWNERS line. An escaped \# is part of the pattern.
+func stripComment(line string) string {
+	for i := 1; i < len(line); i++ {
+		if line[i] == '#' && (line[i-1] == ' ' || line[i-1] == '\t') {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// compilePattern turns a gitignore-style CODEOWNERS pattern into a regular
+// expression matched against slash-separated paths relative to the base.
+// Patterns without a slash match at any depth, and a pattern matching a
+// directory also matches everything below it, except for "dir/*" which
+// GitHub documents as not matching nested directories.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "/**") && i+3 == len(pattern):
+			b.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			b.WriteString("[^/]*")
+		case pattern[i] == '?':
+			b.WriteString("[^/]")
+		case pattern[i] == '\\' && i+1 < len(pattern): // \# and \* stand for themselves
+			i++
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	switch {
+	case dirOnly:
+		b.WriteString("/.*$")
+	case strings.HasSuffix(pattern, "/*"):
+		b.WriteString("$")
+	default:
+		b.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(b.String())
+}
+
+// Of returns the owners of path, which is either absolute or relative to the
+// current directory. As in CODEOWNERS, the last matching rule wins; a
+// matching rule without owners leaves the file unowned.
+func (o *Owners) Of(path string) []string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil
+	}
+	rel, err := filepath.Rel(o.Base, abs)
+	rel = filepath.ToSlash(rel)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+		return nil
+	}
+	for i := len(o.rules) - 1; i >= 0; i-- {
+		if o.rules[i].pattern.MatchString(rel) {
+			return o.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// Annotate sets the owners of every finding.
+func (o *Owners) Annotate(findings []analyzer.Finding) {
+	cache := map[string][]string{}
+	for i := range findings {
+		owners, ok := cache[findings[i].File]
+		if !ok {
+			owners = o.Of(findings[i].File)
+			cache[findings[i].File] = owners
+		}
+		findings[i].Owners = owners
+	}
+}
+
+// Filter returns the findings owned by owner. Matching ignores case, as team
+// and user handles do on GitHub.
+func Filter(findings []analyzer.Finding, owner string) []analyzer.Finding {
+	var result []analyzer.Finding
+	for _, f := range findings {
+		for _, o := range f.Owners {
+			if strings.EqualFold(o, owner) {
+				result = append(result, f)
+				break
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,120 @@
+package owners
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCompilePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*", "main.go", true},
+		{"*", "a/b/main.go", true},
+		{"*.go", "main.go", true},
+		{"*.go", "pkg/x/main.go", true},
+		{"*.go", "main.go.txt", false},
+		{"/main.go", "main.go", true},
+		{"/main.go", "cmd/main.go", false},
+		{"main.go", "cmd/main.go", true},
+		{"docs/", "docs/index.md", true},
+		{"docs/", "src/docs/index.md", true},
+		{"docs/", "docs", false},
+		{"/docs/", "src/docs/index.md", false},
+		{"apps/", "apps/web/main.go", true},
+		{"apps/web", "apps/web/main.go", true},
+		{"apps/web", "x/apps/web/main.go", false},
+		{"docs/*", "docs/index.md", true},
+		{"docs/*", "docs/api/index.md", false},
+		{"**/logs", "logs/a.log", true},
+		{"**/logs", "build/logs/a.log", true},
+		{"docs/**", "docs/a/b.md", true},
+		{"a/**/b", "a/b", true},
+		{"a/**/b", "a/x/y/b", true},
+		{"file?.go", "file1.go", true},
+		{"file?.go", "file10.go", false},
+		{"a+b.go", "a+b.go", true},
+		{"a+b.go", "aab.go", false},
+		{`\#notes.md`, "#notes.md", true},
+		{`docs/\#*`, "docs/#1.md", true},
+	}
+	for _, tt := range tests {
+		re, err := compilePattern(tt.pattern)
+		if err != nil {
+			t.Errorf("compilePattern(%q): %v", tt.pattern, err)
+			continue
+		}
+		if got := re.MatchString(tt.path); got != tt.want {
+			t.Errorf("compilePattern(%q) matches %q = %v, want %v (regexp %s)", tt.pattern, tt.path, got, tt.want, re)
+		}
+	}
+}
+
+func TestStripComment(t *testing.T) {
+	tests := map[string]string{
+		"*.go @dev":              "*.go @dev",
+		"*.go @dev # Go code":    "*.go @dev ",
+		"*.go @dev\t# Go code":   "*.go @dev\t",
+		`\#notes.md @docs`:       `\#notes.md @docs`,
+		`docs/\#x @docs # notes`: `docs/\#x @docs `,
+	}
+	for line, want := range tests {
+		if got := stripComment(line); got != want {
+			t.Errorf("stripComment(%q) = %q, want %q", line, got, want)
+		}
+	}
+}
+
+func write(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFind(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	base := t.TempDir()
+	// a CODEOWNERS file above the repository must not be used
+	write(t, filepath.Join(base, "CODEOWNERS"), "* @outside\n")
+	repo := filepath.Join(base, "repo")
+	write(t, filepath.Join(repo, "pkg", "a.go"), "package pkg\n")
+	if out, err := exec.Command("git", "init", "-q", repo).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+	if o, err := Find(filepath.Join(repo, "pkg")); err != nil || o != nil {
+		t.Fatalf("Find without CODEOWNERS in the repository = %v, %v, want nil", o, err)
+	}
+
+	write(t, filepath.Join(repo, ".github", "CODEOWNERS"), "* @dev # everyone\n..config/ @ops\n")
+	o, err := Find(filepath.Join(repo, "pkg"))
+	if err != nil || o == nil {
+		t.Fatalf("Find = %v, %v, want the repository CODEOWNERS", o, err)
+	}
+	if got := o.Of(filepath.Join(repo, "pkg", "a.go")); !reflect.DeepEqual(got, []string{"@dev"}) {
+		t.Errorf("Of(pkg/a.go) = %v, want [@dev]", got)
+	}
+	if got := o.Of(filepath.Join(repo, "..config", "x")); !reflect.DeepEqual(got, []string{"@ops"}) {
+		t.Errorf("Of(..config/x) = %v, want [@ops]", got)
+	}
+	if got := o.Of(filepath.Join(base, "other.go")); got != nil {
+		t.Errorf("Of outside the repository = %v, want nil", got)
+	}
+
+	// outside git only the scanned directory itself is searched
+	plain := filepath.Join(base, "plain")
+	write(t, filepath.Join(plain, "a.go"), "package plain\n")
+	if o, err := Find(plain); err != nil || o != nil {
+		t.Errorf("Find outside git = %v, %v, want nil", o, err)
+	}
+}
@@ -0,0 +1,156 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/gotech-hub/gocheck/analyzer"
+)
+
+// annotationFormats are the CI log formats understood by NewAnnotationWriter.
+var annotationFormats = map[string]bool{
+	"github":   true,
+	"azure":    true,
+	"teamcity": true,
+}
+
+// IsAnnotationFormat reports whether format is a CI annotation format.
+func IsAnnotationFormat(format string) bool {
+	return annotationFormats[format]
+}
+
+// AnnotationWriter streams findings as CI workflow commands, one line per
+// finding, so a CI system can attach them to the offending lines. Findings
+// can be written as soon as they are known; Flush must be called at the end.
+type AnnotationWriter struct {
+	w      *bufio.Writer
+	format string
+	// inspection types already declared, TeamCity requires each type to be
+	// declared once before it is used
+	declared map[string]bool
+}
+
+// NewAnnotationWriter returns a writer producing format, one of "github",
+// "azure" or "teamcity".
+func NewAnnotationWriter(w io.Writer, format string) (*AnnotationWriter, error) {
+	if !IsAnnotationFormat(format) {
+		return nil, fmt.Errorf("unknown annotation format %q", format)
+	}
+	return &AnnotationWriter{w: bufio.NewWriter(w), format: format, declared: map[string]bool{}}, nil
+}
+
+// Write emits the line(s) for one finding.
+func (a *AnnotationWriter) Write(f analyzer.Finding) error {
+	path := relPath(".", f.File)
+	var err error
+	switch a.format {
+	case "github":
+		_, err = fmt.Fprintf(a.w, "::%s %s::%s\n", githubLevel(f.Severity), githubProperties(f, path), githubEscape(annotationMessage(f)))
+	case "azure":
+		_, err = fmt.Fprintf(a.w, "##vso[task.logissue type=%s;sourcepath=%s;linenumber=%d;columnnumber=%d;code=%s;]%s\n",
+			azureLevel(f.Severity), azureEscape(path), f.Line, max(f.Column, 1), azureEscape(f.Rule), azureEscape(annotationMessage(f)))
+	case "teamcity":
+		if !a.declared[f.Rule] {
+			a.declared[f.Rule] = true
+			_, err = fmt.Fprintf(a.w, "##teamcity[inspectionType id='%s' name='%s' category='%s' description='%s']\n",
+				teamcityEscape(f.Rule), teamcityEscape(f.Rule), teamcityEscape(f.Category), teamcityEscape(f.Category+" rule "+f.Rule))
+			if err != nil {
+				return err
+			}
+		}
+		_, err = fmt.Fprintf(a.w, "##teamcity[inspection typeId='%s' message='%s' file='%s' line='%d' SEVERITY='%s']\n",
+			teamcityEscape(f.Rule), teamcityEscape(annotationMessage(f)), teamcityEscape(path), f.Line, teamcityLevel(f.Severity))
+	}
+	return err
+}
+
+// Flush writes any buffered lines.
+func (a *AnnotationWriter) Flush() error {
+	return a.w.Flush()
+}
+
+// WriteAnnotations writes every finding to w in format, most severe first so
+// that CI systems which cap the number of annotations keep the important ones.
+func WriteAnnotations(w io.Writer, format string, findings []analyzer.Finding) error {
+	aw, err := NewAnnotationWriter(w, format)
+	if err != nil {
+		return err
+	}
+	sorted := make([]analyzer.Finding, len(findings))
+	copy(sorted, findings)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return severityRank(sorted[i].Severity) > severityRank(sorted[j].Severity)
+	})
+	for _, f := range sorted {
+		if err := aw.Write(f); err != nil {
+			return err
+		}
+	}
+	return aw.Flush()
+}
+
+func annotationMessage(f analyzer.Finding) string {
+	if f.Suggestion == "" {
+		return f.Message
+	}
+	return f.Message + "\n" + f.Suggestion
+}
+
+func githubLevel(s analyzer.Severity) string {
+	switch s {
+	case analyzer.Critical, analyzer.High:
+		return "error"
+	case analyzer.Medium:
+		return "warning"
+	}
+	return "notice"
+}
+
+func githubProperties(f analyzer.Finding, path string) string {
+	props := []string{"file=" + githubEscapeProperty(path), fmt.Sprintf("line=%d", f.Line)}
+	if f.EndLine > f.Line {
+		props = append(props, fmt.Sprintf("endLine=%d", f.EndLine))
+	}
+	if f.Column > 0 {
+		props = append(props, fmt.Sprintf("col=%d", f.Column))
+	}
+	props = append(props, "title="+githubEscapeProperty(f.Rule))
+	return strings.Join(props, ",")
+}
+
+var (
+	githubDataEscaper     = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	githubPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+func githubEscape(s string) string         { return githubDataEscaper.Replace(s) }
+func githubEscapeProperty(s string) string { return githubPropertyEscaper.Replace(s) }
+
+// Azure Pipelines only knows errors and warnings.
+func azureLevel(s analyzer.Severity) string {
+	if s == analyzer.Critical || s == analyzer.High {
+		return "error"
+	}
+	return "warning"
+}
+
+var azureEscaper = strings.NewReplacer("%", "%AZP25", ";", "%3B", "\r", "%0D", "\n", "%0A", "]", "%5D")
+
+func azureEscape(s string) string { return azureEscaper.Replace(s) }
+
+func teamcityLevel(s analyzer.Severity) string {
+	switch s {
+	case analyzer.Critical, analyzer.High:
+		return "ERROR"
+	case analyzer.Medium:
+		return "WARNING"
+	}
+	return "WEAK WARNING"
+}
+
+var teamcityEscaper = strings.NewReplacer("|", "||", "'", "|'", "\n", "|n", "\r", "|r", "[", "|[", "]", "|]")
+
+func teamcityEscape(s string) string { return teamcityEscaper.Replace(s) }
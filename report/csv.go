@@ -0,0 +1,81 @@
+package report
+
+import (
+	"encoding/csv"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gotech-hub/gocheck/analyzer"
+)
+
+// GenerateCSV writes report.csv with one row per finding. With pivot set it
+// also writes report-rules.csv, a per-rule summary with counts by severity.
+// Quoting and CRLF line endings follow RFC 4180, so both files open directly
+// in spreadsheets.
+func GenerateCSV(findings []analyzer.Finding, meta Metadata, pivot bool) error {
+	f, err := os.Create("report.csv")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := csv.NewWriter(f)
+	w.UseCRLF = true
+	w.Write([]string{"rule_id", "category", "severity", "file", "line", "column", "message", "suggestion", "owner"})
+	for _, finding := range findings {
+		w.Write([]string{
+			csvText(finding.Rule),
+			csvText(finding.Category),
+			string(finding.Severity),
+			csvText(relPath(meta.Root, finding.File)),
+			strconv.Itoa(finding.Line),
+			strconv.Itoa(finding.Column),
+			csvText(finding.Message),
+			csvText(finding.Suggestion),
+			csvText(strings.Join(finding.Owners, " ")),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+
+	if pivot {
+		return generateRulePivot(findings)
+	}
+	return nil
+}
+
+func generateRulePivot(findings []analyzer.Finding) error {
+	f, err := os.Create("report-rules.csv")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := csv.NewWriter(f)
+	w.UseCRLF = true
+	w.Write([]string{"rule_id", "category", "total", "critical", "high", "medium", "low"})
+	for _, r := range ruleStats(findings) {
+		w.Write([]string{
+			csvText(r.Rule),
+			csvText(r.Category),
+			strconv.Itoa(r.Count),
+			strconv.Itoa(r.Severity[analyzer.Critical]),
+			strconv.Itoa(r.Severity[analyzer.High]),
+			strconv.Itoa(r.Severity[analyzer.Medium]),
+			strconv.Itoa(r.Severity[analyzer.Low]),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// csvText keeps a spreadsheet from evaluating a cell as a formula: messages
+// and suggestions quote scanned code, so a cell starting with =, +, - or @
+// is prefixed with a single quote.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
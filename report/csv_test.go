@@ -0,0 +1,50 @@
+package report
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gotech-hub/gocheck/analyzer"
+)
+
+func TestGenerateCSV(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	findings := []analyzer.Finding{
+		{Rule: "r1", Category: "Clean", Severity: analyzer.High, File: "/src/a.go", Line: 3, Message: "=HYPERLINK(\"x\")", Suggestion: "-1, \"quoted\""},
+		{Rule: "r1", Category: "Clean", Severity: analyzer.Low, File: "/src/a.go", Line: 4, Message: "plain"},
+		{Rule: "r2", Category: "Errors", Severity: analyzer.High, File: "/src/b.go", Line: 1, Message: "@cmd"},
+	}
+	if err := GenerateCSV(findings, Metadata{Root: "/src"}, true); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile("report.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "rule_id,category,severity,file,line,column,message,suggestion,owner\r\n" +
+		"r1,Clean,High,a.go,3,0,\"'=HYPERLINK(\"\"x\"\")\",\"'-1, \"\"quoted\"\"\",\r\n" +
+		"r1,Clean,Low,a.go,4,0,plain,,\r\n" +
+		"r2,Errors,High,b.go,1,0,'@cmd,,\r\n"
+	if string(data) != want {
+		t.Errorf("report.csv =\n%q\nwant\n%q", data, want)
+	}
+
+	data, err = os.ReadFile("report-rules.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = "rule_id,category,total,critical,high,medium,low\r\n" +
+		"r1,Clean,2,0,1,0,1\r\n" +
+		"r2,Errors,1,0,1,0,0\r\n"
+	if string(data) != want {
+		t.Errorf("report-rules.csv =\n%q\nwant\n%q", data, want)
+	}
+}
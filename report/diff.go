@@ -0,0 +1,181 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gotech-hub/gocheck/analyzer"
+)
+
+// DiffResult splits the findings of two reports into those that were fixed
+// (only in the old report), new (only in the new report) and unchanged.
+type DiffResult struct {
+	Fixed     []analyzer.Finding `json:"fixed"`
+	New       []analyzer.Finding `json:"new"`
+	Unchanged []analyzer.Finding `json:"unchanged"`
+}
+
+// LoadJSON reads the findings of a report written by GenerateJSON, either
+// the current JSONReport document or the bare array of older versions.
+func LoadJSON(path string) ([]analyzer.Finding, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var findings []analyzer.Finding
+	if len(bytes.TrimSpace(data)) > 0 && bytes.TrimSpace(data)[0] == '[' {
+		err = json.Unmarshal(data, &findings)
+	} else {
+		var doc JSONReport
+		err = json.Unmarshal(data, &doc)
+		findings = doc.Findings
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a gocheck JSON report: %v", path, err)
+	}
+	return findings, nil
+}
+
+// diffKey identifies a finding across reports. Reports written before
+// fingerprints existed fall back to the rule, file and message, which is
+// still independent of the line number.
+func diffKey(f analyzer.Finding) string {
+	if f.Fingerprint != "" {
+		return f.Fingerprint
+	}
+	return f.Category + "\x00" + f.Rule + "\x00" + f.File + "\x00" + f.Message
+}
+
+// Diff matches the findings of two reports by fingerprint. A key reported n
+// times in old and m times in new counts min(n, m) findings as unchanged.
+func Diff(old, new []analyzer.Finding) DiffResult {
+	remaining := map[string]int{}
+	for _, f := range old {
+		remaining[diffKey(f)]++
+	}
+	var d DiffResult
+	for _, f := range new {
+		k := diffKey(f)
+		if remaining[k] > 0 {
+			remaining[k]--
+			d.Unchanged = append(d.Unchanged, f)
+		} else {
+			d.New = append(d.New, f)
+		}
+	}
+	for _, f := range old {
+		k := diffKey(f)
+		if remaining[k] > 0 {
+			remaining[k]--
+			d.Fixed = append(d.Fixed, f)
+		}
+	}
+	return d
+}
+
+// WriteDiffText writes a plain-text summary followed by the new and fixed
+// findings.
+func WriteDiffText(w io.Writer, d DiffResult) {
+	fmt.Fprintf(w, "New: %d  Fixed: %d  Unchanged: %d\n", len(d.New), len(d.Fixed), len(d.Unchanged))
+	for _, section := range []struct {
+		Title    string
+		Findings []analyzer.Finding
+	}{{"New findings", d.New}, {"Fixed findings", d.Fixed}} {
+		if len(section.Findings) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n%s:\n", section.Title)
+		for _, f := range section.Findings {
+			fmt.Fprintf(w, "  [%s] %s:%d %s (%s)\n", f.Severity, f.File, f.Line, f.Message, f.Rule)
+		}
+	}
+}
+
+// WriteDiffJSON writes the diff as an indented JSON object.
+func WriteDiffJSON(w io.Writer, d DiffResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d)
+}
+
+// WriteDiffMarkdown writes the diff as Markdown tables, suitable for release
+// notes or a pull-request comment.
+func WriteDiffMarkdown(w io.Writer, d DiffResult) {
+	fmt.Fprintln(w, "## GoCheck diff")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| New | Fixed | Unchanged |")
+	fmt.Fprintln(w, "| ---: | ---: | ---: |")
+	fmt.Fprintf(w, "| %d | %d | %d |\n", len(d.New), len(d.Fixed), len(d.Unchanged))
+	for _, section := range []struct {
+		Title    string
+		Findings []analyzer.Finding
+	}{{"New findings", d.New}, {"Fixed findings", d.Fixed}} {
+		if len(section.Findings) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\n### %s\n\n", section.Title)
+		fmt.Fprintln(w, "| Severity | Rule | Location | Message |")
+		fmt.Fprintln(w, "| --- | --- | --- | --- |")
+		for _, f := range section.Findings {
+			fmt.Fprintf(w, "| %s | `%s` | `%s:%d` | %s |\n", f.Severity, f.Rule, f.File, f.Line, markdownEscape(f.Message))
+		}
+	}
+}
+
+// markdownEscape keeps a message on one table row.
+func markdownEscape(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// WriteDiffHTML writes the diff as a standalone HTML page.
+func WriteDiffHTML(w io.Writer, d DiffResult) error {
+	tmpl := `
+    <html>
+    <head>
+    <meta charset="utf-8">
+    <style>
+        body { font-family: Arial; background: #f9f9f9; padding: 20px; }
+        h1, h2 { color: #333; }
+        .stats { background: #fff; border-radius: 8px; padding: 16px; margin-bottom: 24px; box-shadow: 0 2px 8px #eee; display: flex; gap: 24px; }
+        .stat { display: flex; flex-direction: column; align-items: center; min-width: 80px; }
+        .stat-label { font-size: 14px; color: #888; }
+        .stat-value { font-size: 24px; font-weight: bold; }
+        .Low { background-color: #f6ffed; border-left: 5px solid #95de64; }
+        .Medium { background-color: #fffbe6; border-left: 5px solid #fadb14; }
+        .High { background-color: #fff2e8; border-left: 5px solid #fa8c16; }
+        .Critical { background-color: #fff1f0; border-left: 5px solid #ff4d4f; }
+        .finding { padding: 10px; border-radius: 5px; margin-bottom: 10px; }
+        .fixed .finding { opacity: 0.7; text-decoration: line-through; }
+        .file { font-weight: bold; }
+        .rule { font-family: Menlo, Consolas, monospace; font-size: 13px; color: #555; }
+    </style>
+    </head>
+    <body>
+        <h1>GoCheck Diff</h1>
+        <div class="stats">
+            <div class="stat"><span class="stat-label">New</span><span class="stat-value">{{len .New}}</span></div>
+            <div class="stat"><span class="stat-label">Fixed</span><span class="stat-value">{{len .Fixed}}</span></div>
+            <div class="stat"><span class="stat-label">Unchanged</span><span class="stat-value">{{len .Unchanged}}</span></div>
+        </div>
+        <h2>New findings</h2>
+        <div>{{range .New}}{{template "finding" .}}{{else}}<p>No new findings.</p>{{end}}</div>
+        <h2>Fixed findings</h2>
+        <div class="fixed">{{range .Fixed}}{{template "finding" .}}{{else}}<p>No fixed findings.</p>{{end}}</div>
+    </body>
+    </html>
+    {{define "finding"}}
+        <div class="finding {{.Severity}}">
+            <div><span class="file">{{.File}}:{{.Line}}</span> <span class="rule">{{.Rule}}</span></div>
+            <div>{{.Message}}</div>
+        </div>
+    {{end}}`
+
+	t := template.Must(template.New("diff").Parse(tmpl))
+	return t.Execute(w, d)
+}
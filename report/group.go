@@ -0,0 +1,219 @@
+package report
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gotech-hub/gocheck/analyzer"
+)
+
+// severities lists the severity levels from least to most severe.
+var severities = []analyzer.Severity{analyzer.Low, analyzer.Medium, analyzer.High, analyzer.Critical}
+
+// categoryLabels gives the built-in categories a friendlier name; categories
+// without an entry are shown as-is.
+var categoryLabels = map[string]string{
+	"Clean": "Clean Code",
+}
+
+// categoryOrder keeps the built-in categories in their historical tab order.
+var categoryOrder = map[string]int{
+	"Clean":       1,
+	"Performance": 2,
+	"Security":    3,
+	"Duplication": 4,
+	"Errors":      5,
+}
+
+// CategoryGroup is the set of findings reported under one category.
+type CategoryGroup struct {
+	Name     string
+	Label    string
+	Findings []analyzer.Finding
+}
+
+// severityStats counts findings per severity, always including every level.
+func severityStats(findings []analyzer.Finding) map[string]int {
+	stats := map[string]int{}
+	for _, s := range severities {
+		stats[string(s)] = 0
+	}
+	for _, f := range findings {
+		stats[string(f.Severity)]++
+	}
+	return stats
+}
+
+// groupByCategory splits findings by category, built-in categories first and
+// the rest in alphabetical order.
+func groupByCategory(findings []analyzer.Finding) []CategoryGroup {
+	index := map[string]int{}
+	var groups []CategoryGroup
+	for _, f := range findings {
+		i, ok := index[f.Category]
+		if !ok {
+			label := categoryLabels[f.Category]
+			if label == "" {
+				label = f.Category
+			}
+			i = len(groups)
+			index[f.Category] = i
+			groups = append(groups, CategoryGroup{Name: f.Category, Label: label})
+		}
+		groups[i].Findings = append(groups[i].Findings, f)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		oi, oj := categoryOrder[groups[i].Name], categoryOrder[groups[j].Name]
+		if oi == 0 {
+			oi = len(categoryOrder) + 1
+		}
+		if oj == 0 {
+			oj = len(categoryOrder) + 1
+		}
+		if oi != oj {
+			return oi < oj
+		}
+		return groups[i].Name < groups[j].Name
+	})
+	return groups
+}
+
+// relPath returns file relative to root in slash form, or file itself if it
+// lies outside root. Scanned files and tool output (gosec reports absolute
+// paths) are normalized this way before being aggregated.
+func relPath(root, file string) string {
+	if root != "" {
+		absRoot, errRoot := filepath.Abs(root)
+		absFile, errFile := filepath.Abs(file)
+		if errRoot == nil && errFile == nil {
+			rel, err := filepath.Rel(absRoot, absFile)
+			if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return filepath.ToSlash(rel)
+			}
+		}
+	}
+	return filepath.ToSlash(file)
+}
+
+// packageOf returns the directory of file in slash form, which is what the
+// reports use to identify a package.
+func packageOf(file string) string {
+	return filepath.ToSlash(filepath.Dir(file))
+}
+
+// FindingGroup is a set of findings sharing the same key.
+type FindingGroup struct {
+	Key      string
+	Findings []analyzer.Finding
+}
+
+// groupFindings groups findings by key, in key order.
+func groupFindings(findings []analyzer.Finding, key func(analyzer.Finding) string) []FindingGroup {
+	index := map[string]int{}
+	var groups []FindingGroup
+	for _, f := range findings {
+		k := key(f)
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, FindingGroup{Key: k})
+		}
+		groups[i].Findings = append(groups[i].Findings, f)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Key < groups[j].Key })
+	return groups
+}
+
+// unowned is the owner key of findings without a CODEOWNERS entry.
+const unowned = "(unowned)"
+
+// ownerOf returns the owners of f as a single grouping key.
+func ownerOf(f analyzer.Finding) string {
+	if len(f.Owners) == 0 {
+		return unowned
+	}
+	return strings.Join(f.Owners, " ")
+}
+
+// hasOwners reports whether any finding was attributed to an owner.
+func hasOwners(findings []analyzer.Finding) bool {
+	for _, f := range findings {
+		if len(f.Owners) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ownerList returns every individual owner of the findings, followed by
+// the unowned key if some findings have no owner. It is empty when no
+// CODEOWNERS file was used.
+func ownerList(findings []analyzer.Finding) []string {
+	if !hasOwners(findings) {
+		return nil
+	}
+	seen := map[string]bool{}
+	var list []string
+	anyUnowned := false
+	for _, f := range findings {
+		if len(f.Owners) == 0 {
+			anyUnowned = true
+		}
+		for _, o := range f.Owners {
+			if !seen[o] {
+				seen[o] = true
+				list = append(list, o)
+			}
+		}
+	}
+	sort.Strings(list)
+	if anyUnowned {
+		list = append(list, unowned)
+	}
+	return list
+}
+
+// DefaultRecentDays is the age under which a blamed finding counts as
+// recently introduced when Metadata.RecentDays is not set.
+const DefaultRecentDays = 30
+
+// isRecent reports whether f was introduced within the recent window. Only
+// findings annotated with --blame have an age.
+func isRecent(f analyzer.Finding, meta Metadata) bool {
+	if f.Blame == nil {
+		return false
+	}
+	days := meta.RecentDays
+	if days <= 0 {
+		days = DefaultRecentDays
+	}
+	return time.Since(f.Blame.Date) < time.Duration(days)*24*time.Hour
+}
+
+// hasBlame reports whether any finding carries blame information.
+func hasBlame(findings []analyzer.Finding) bool {
+	for _, f := range findings {
+		if f.Blame != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// distinct returns the sorted, non-empty values of key over findings.
+func distinct(findings []analyzer.Finding, key func(analyzer.Finding) string) []string {
+	seen := map[string]bool{}
+	var values []string
+	for _, f := range findings {
+		v := key(f)
+		if v != "" && !seen[v] {
+			seen[v] = true
+			values = append(values, v)
+		}
+	}
+	sort.Strings(values)
+	return values
+}
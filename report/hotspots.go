@@ -0,0 +1,100 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gotech-hub/gocheck/hotspot"
+)
+
+const (
+	hotspotWidth   = 640
+	hotspotHeight  = 320
+	hotspotPadding = 40
+	hotspotChartN  = 200 // points drawn at most, the highest ranked ones
+)
+
+func hotspotName(h hotspot.Hotspot, root string) string {
+	if h.Func == "" {
+		return relPath(root, h.File)
+	}
+	return fmt.Sprintf("%s:%d %s", relPath(root, h.File), h.Line, h.Func)
+}
+
+// WriteHotspotsText writes the hotspots as a ranked table. Hot entries, high
+// on both churn and complexity, are marked with an asterisk.
+func WriteHotspotsText(w io.Writer, hotspots []hotspot.Hotspot, root string) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Rank\tScore\tChurn\tComplexity\tFindings\tLocation")
+	for i, h := range hotspots {
+		mark := ""
+		if h.Hot {
+			mark = " *"
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%d\t%d\t%s%s\n", i+1, h.Score, h.Churn, h.Complexity, h.Findings, hotspotName(h, root), mark)
+	}
+	tw.Flush()
+}
+
+// WriteHotspotsJSON writes the hotspots as a JSON array, highest ranked first.
+func WriteHotspotsJSON(w io.Writer, hotspots []hotspot.Hotspot) error {
+	if hotspots == nil {
+		hotspots = []hotspot.Hotspot{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(hotspots)
+}
+
+// hotspotChart renders hotspots as an inline SVG scatter chart with churn on
+// the x axis and complexity on the y axis; the dot size grows with the
+// number of findings and hot entries are drawn in red. It returns "" when
+// there is nothing to draw.
+func hotspotChart(hotspots []hotspot.Hotspot, root string) template.HTML {
+	if len(hotspots) > hotspotChartN {
+		hotspots = hotspots[:hotspotChartN]
+	}
+	maxChurn, maxComplexity := 1, 1
+	changed := false
+	for _, h := range hotspots {
+		maxChurn = max(maxChurn, h.Churn)
+		maxComplexity = max(maxComplexity, h.Complexity)
+		changed = changed || h.Churn > 0
+	}
+	if !changed {
+		return ""
+	}
+	plotW := float64(hotspotWidth - 2*hotspotPadding)
+	plotH := float64(hotspotHeight - 2*hotspotPadding)
+	x := func(v int) float64 { return hotspotPadding + plotW*float64(v)/float64(maxChurn) }
+	y := func(v int) float64 { return hotspotPadding + plotH - plotH*float64(v)/float64(maxComplexity) }
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg class="hotspots" viewBox="0 0 %d %d" width="100%%" xmlns="http://www.w3.org/2000/svg" font-size="11" font-family="Arial">`, hotspotWidth, hotspotHeight)
+	// the hot quadrant: upper half of both churn and complexity
+	fmt.Fprintf(&b, `<rect x="%.1f" y="%d" width="%.1f" height="%.1f" fill="#fff1f0"/>`, x(0)+plotW/2, hotspotPadding, plotW/2, plotH/2)
+	fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#ddd"/>`, hotspotPadding, y(0), hotspotWidth-hotspotPadding, y(0))
+	fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%.1f" stroke="#ddd"/>`, hotspotPadding, hotspotPadding, hotspotPadding, y(0))
+	fmt.Fprintf(&b, `<text x="%d" y="%.1f" text-anchor="end" fill="#888">%d</text>`, hotspotWidth-hotspotPadding, y(0)+16, maxChurn)
+	fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle" fill="#888">churn (commits)</text>`, x(0)+plotW/2, y(0)+16)
+	fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="end" fill="#888">%d</text>`, hotspotPadding-6, hotspotPadding+4, maxComplexity)
+	fmt.Fprintf(&b, `<text x="%d" y="%d" fill="#888">complexity</text>`, hotspotPadding, hotspotPadding-10)
+	// lowest ranked first so the hottest dots end up on top
+	for i := len(hotspots) - 1; i >= 0; i-- {
+		h := hotspots[i]
+		color := "#1890ff"
+		if h.Hot {
+			color = "#ff4d4f"
+		}
+		r := 3 + min(h.Findings, 12)
+		fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="%d" fill="%s" fill-opacity="0.6"><title>%s&#10;churn %d, complexity %d, findings %d</title></circle>`,
+			x(h.Churn), y(h.Complexity), r, color, html.EscapeString(hotspotName(h, root)), h.Churn, h.Complexity, h.Findings)
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
@@ -1,55 +1,125 @@
 package report
 
 import (
+	"fmt"
 	"html/template"
 	"os"
+	"strings"
 
 	"github.com/gotech-hub/gocheck/analyzer"
+	"github.com/gotech-hub/gocheck/history"
+	"github.com/gotech-hub/gocheck/hotspot"
+	"github.com/gotech-hub/gocheck/vcs"
 )
 
-func GenerateHTML(findings []analyzer.Finding) {
-	// Tính toán số lượng từng loại severity
-	stats := map[string]int{
-		"Low":      0,
-		"Medium":   0,
-		"High":     0,
-		"Critical": 0,
+func GenerateHTML(findings []analyzer.Finding, meta Metadata) {
+	type FindingView struct {
+		analyzer.Finding
+		Index   int
+		Package string
+		Owner   string
+		Age     int64 // unix time the offending line was last changed, 0 without --blame
+		Recent  bool
+		Search  string
+		Snippet []SnippetLine
+		Copies  []CloneSnippet // side by side for duplicated code
 	}
-	for _, f := range findings {
-		stats[string(f.Severity)]++
+	type CategoryTab struct {
+		Name  string
+		Label string
+		Count int
+	}
+	type HotspotView struct {
+		hotspot.Hotspot
+		Name string
 	}
 	type ReportData struct {
-		Findings            []analyzer.Finding
-		Stats               map[string]int
-		Total               int
-		CleanCodeFindings   []analyzer.Finding
-		PerformanceFindings []analyzer.Finding
-		SecurityFindings    []analyzer.Finding
+		Findings   []FindingView
+		Stats      map[string]int
+		Severities []string
+		Total      int
+		Categories []CategoryTab
+		Rules      []string
+		Packages   []string
+		Owners     []string
+		Blame      bool
+		Coverage   bool
+		RecentDays int
+		Recent     int
+		Legacy     int
+		Overview   Overview
+		Trend      template.HTML
+		TrendDelta *history.Delta
+		Hotspots   template.HTML
+		TopHotspot []HotspotView
+	}
+
+	// Tabs được sinh từ các category có trong findings
+	var tabs []CategoryTab
+	for _, g := range groupByCategory(findings) {
+		tabs = append(tabs, CategoryTab{Name: g.Name, Label: g.Label, Count: len(g.Findings)})
 	}
-	// Phân loại findings
-	var cleanCodeFindings, performanceFindings, securityFindings []analyzer.Finding
-	for _, f := range findings {
-		// Dựa vào Message để phân loại (theo cách gọi trong analyzer.go)
-		if f.Category == "Clean" {
-			cleanCodeFindings = append(cleanCodeFindings, f)
-		} else if f.Category == "Performance" {
-			performanceFindings = append(performanceFindings, f)
-		} else if f.Category == "Security" {
-			securityFindings = append(securityFindings, f)
+
+	// Kèm đoạn mã nguồn xung quanh mỗi finding
+	sources := sourceCache{}
+	views := make([]FindingView, 0, len(findings))
+	for i, f := range findings {
+		views = append(views, FindingView{
+			Finding: f,
+			Index:   i,
+			Package: packageOf(relPath(meta.Root, f.File)),
+			Owner:   ownerOf(f),
+			Recent:  isRecent(f, meta),
+			Search:  strings.ToLower(strings.Join([]string{f.Rule, f.File, f.Message, f.Suggestion, ownerOf(f)}, " ")),
+			Snippet: sources.snippet(f),
+			Copies:  sources.clones(f),
+		})
+		if f.Blame != nil {
+			views[i].Age = f.Blame.Date.Unix()
 		}
 	}
+
 	data := ReportData{
-		Findings:            findings,
-		Stats:               stats,
-		Total:               len(findings),
-		CleanCodeFindings:   cleanCodeFindings,
-		PerformanceFindings: performanceFindings,
-		SecurityFindings:    securityFindings,
+		Findings:   views,
+		Stats:      severityStats(findings),
+		Severities: []string{"Low", "Medium", "High", "Critical"},
+		Total:      len(findings),
+		Categories: tabs,
+		Rules:      distinct(findings, func(f analyzer.Finding) string { return f.Rule }),
+		Packages:   distinct(findings, func(f analyzer.Finding) string { return packageOf(relPath(meta.Root, f.File)) }),
+		Overview:   buildOverview(findings, meta),
+		Owners:     ownerList(findings),
+		Blame:      hasBlame(findings),
+		Coverage:   meta.Coverage != nil,
+		RecentDays: meta.RecentDays,
+		Trend:      trendChart(meta.History),
+		Hotspots:   hotspotChart(meta.Hotspots, meta.Root),
+	}
+	for _, h := range meta.Hotspots {
+		if h.Churn == 0 || len(data.TopHotspot) == overviewTopN {
+			break
+		}
+		data.TopHotspot = append(data.TopHotspot, HotspotView{Hotspot: h, Name: hotspotName(h, meta.Root)})
+	}
+	if data.RecentDays <= 0 {
+		data.RecentDays = DefaultRecentDays
+	}
+	for _, v := range views {
+		if v.Recent {
+			data.Recent++
+		} else if v.Blame != nil {
+			data.Legacy++
+		}
+	}
+	if n := len(meta.History); n > 1 {
+		total := history.Compare(meta.History[n-2], meta.History[n-1]).Total
+		data.TrendDelta = &total
 	}
 
 	tmpl := `
     <html>
     <head>
+    <meta charset="utf-8">
     <style>
         body { font-family: Arial; background: #f9f9f9; padding: 20px; }
         h1 { color: #333; }
@@ -71,22 +141,63 @@ func GenerateHTML(findings []analyzer.Finding) {
         .suggestion { font-style: italic; color: #555; }
         .tab { display: inline-block; padding: 10px 24px; margin-right: 8px; background: #eee; border-radius: 8px 8px 0 0; cursor: pointer; }
         .tab.active { background: #fff; border-bottom: 2px solid #fff; font-weight: bold; }
-        .tab-content { display: none; background: #fff; border-radius: 0 0 8px 8px; padding: 16px; box-shadow: 0 2px 8px #eee; }
-        .tab-content.active { display: block; }
+        .tab-content { background: #fff; border-radius: 0 0 8px 8px; padding: 16px; box-shadow: 0 2px 8px #eee; }
+        .filters { display: flex; flex-wrap: wrap; gap: 12px; align-items: center; margin-bottom: 12px; font-size: 14px; }
+        .filters input[type=text] { padding: 4px 8px; }
+        .columns, .finding .row { display: grid; grid-template-columns: 90px 200px 1fr 60px; gap: 8px; }
+        .columns { font-size: 13px; color: #888; padding: 0 15px 6px; border-bottom: 1px solid #eee; margin-bottom: 10px; }
+        .columns span { cursor: pointer; user-select: none; }
+        .columns span.sorted::after { content: " ▲"; }
+        .columns span.sorted.desc::after { content: " ▼"; }
+        .rule { font-family: Menlo, Consolas, monospace; font-size: 13px; color: #555; }
+        .count { color: #888; font-weight: normal; }
+        .owner { font-size: 13px; color: #555; margin-top: 4px; }
+        .columns.with-blame, .finding .row.with-blame { grid-template-columns: 90px 200px 1fr 60px 120px; }
+        .columns.with-coverage, .finding .row.with-coverage { grid-template-columns: 90px 200px 1fr 60px 80px; }
+        .columns.with-blame.with-coverage, .finding .row.with-blame.with-coverage { grid-template-columns: 90px 200px 1fr 60px 120px 80px; }
+        .uncovered { color: #c62828; font-weight: bold; }
+        .badge { background: #ff4d4f; color: #fff; border-radius: 8px; padding: 0 6px; font-size: 11px; }
+        details.group { margin-bottom: 12px; }
+        details.group > summary { cursor: pointer; font-weight: bold; padding: 6px 0; }
+        .empty { color: #888; padding: 12px 0; }
+        .snippet { background: #fafafa; border: 1px solid #eee; border-radius: 4px; margin: 8px 0; padding: 4px 0; overflow-x: auto; font-family: Menlo, Consolas, monospace; font-size: 12px; }
+        .snippet .line { display: block; white-space: pre; padding: 0 8px; }
+        .snippet .line.hit { background: #fff1b8; }
+        .snippet .ln { display: inline-block; width: 40px; color: #aaa; text-align: right; margin-right: 12px; user-select: none; }
+        .clones { display: grid; grid-auto-flow: column; grid-auto-columns: minmax(360px, 1fr); gap: 8px; overflow-x: auto; }
+        .clone-loc { font-size: 12px; color: #555; margin-top: 8px; }
+        .clone .snippet { margin-top: 4px; }
+        .hl-kw { color: #0033b3; font-weight: bold; }
+        .hl-str { color: #067d17; }
+        .hl-num { color: #1750eb; }
+        .hl-com { color: #8c8c8c; font-style: italic; }
+        .hl-bi { color: #871094; }
+        .panels { display: grid; grid-template-columns: repeat(auto-fit, minmax(420px, 1fr)); gap: 24px; }
+        .panel.wide { grid-column: 1 / -1; }
+        .panel h3 { margin-top: 0; color: #333; }
+        .panel table { width: 100%; border-collapse: collapse; font-size: 13px; }
+        .panel th { text-align: left; color: #888; font-weight: normal; border-bottom: 1px solid #eee; padding: 4px 6px; }
+        .panel td { padding: 4px 6px; border-bottom: 1px solid #f5f5f5; }
+        .panel td.num, .panel th.num { text-align: right; }
+        .panel a { color: #1750eb; text-decoration: none; }
+        .pkg-row { cursor: pointer; font-weight: bold; }
+        .grade { display: inline-block; min-width: 1.4em; padding: 0 4px; border-radius: 3px; color: #fff; text-align: center; font-weight: bold; }
+        .grade-A { background: #2e7d32; }
+        .grade-B { background: #689f38; }
+        .grade-C { background: #f9a825; }
+        .grade-D { background: #ef6c00; }
+        .grade-F { background: #c62828; }
+        .file-row td:first-child { color: #555; }
+        .toggle { display: inline-block; width: 14px; color: #888; }
     </style>
-    <script>
-        function showTab(tab) {
-            document.querySelectorAll('.tab').forEach(t => t.classList.remove('active'));
-            document.querySelectorAll('.tab-content').forEach(c => c.classList.remove('active'));
-            document.getElementById(tab+'-tab').classList.add('active');
-            document.getElementById(tab+'-content').classList.add('active');
-        }
-        window.onload = function() { showTab('cleancode'); };
-    </script>
     </head>
     <body>
         <h1>GoCheck Report</h1>
         <div class="stats">
+            <div class="stat" title="Maintainability score {{.Overview.Score.Value}} / 100">
+                <span class="stat-label">Grade</span>
+                <span class="stat-value"><span class="grade grade-{{.Overview.Score.Grade}}">{{.Overview.Score.Grade}}</span></span>
+            </div>
             <div class="stat">
                 <span class="stat-label">Total</span>
                 <span class="stat-value">{{.Total}}</span>
@@ -107,49 +218,305 @@ func GenerateHTML(findings []analyzer.Finding) {
                 <span class="stat-label">Critical</span>
                 <span class="stat-value">{{index .Stats "Critical"}}</span>
             </div>
+            {{if .Blame}}
+            <div class="stat">
+                <span class="stat-label">Last {{.RecentDays}} days</span>
+                <span class="stat-value">{{.Recent}}</span>
+            </div>
+            <div class="stat">
+                <span class="stat-label">Legacy</span>
+                <span class="stat-value">{{.Legacy}}</span>
+            </div>
+            {{end}}
         </div>
-        <div>
-            <div id="cleancode-tab" class="tab" onclick="showTab('cleancode')">Clean Code</div>
-            <div id="performance-tab" class="tab" onclick="showTab('performance')">Performance</div>
-            <div id="security-tab" class="tab" onclick="showTab('security')">Security</div>
-        </div>
-        <div id="cleancode-content" class="tab-content">
-            {{range .CleanCodeFindings}}
-                <div class="finding {{.Severity}}">
-                    <div class="file">{{.File}}:{{.Line}}</div>
-                    <div>{{.Message}}</div>
-                    <div class="suggestion">💡 {{.Suggestion}}</div>
-                </div>
-            {{else}}
-                <div>No Clean Code findings.</div>
+        <div id="tabs">
+            <div class="tab" data-view="overview">Overview</div>
+            <div class="tab" data-cat="">All <span class="count">({{.Total}})</span></div>
+            {{range .Categories}}<div class="tab" data-cat="{{.Name}}">{{.Label}} <span class="count">({{.Count}})</span></div>
             {{end}}
         </div>
-        <div id="performance-content" class="tab-content">
-            {{range .PerformanceFindings}}
-                <div class="finding {{.Severity}}">
-                    <div class="file">{{.File}}:{{.Line}}</div>
-                    <div>{{.Message}}</div>
-                    <div class="suggestion">💡 {{.Suggestion}}</div>
+        <div class="tab-content" id="overview">
+            <div class="panels">
+                {{if .Trend}}
+                <div class="panel wide">
+                    <h3>Trend</h3>
+                    {{with .TrendDelta}}<p>Total findings since the previous run: {{.Old}} → {{.New}} ({{if gt .Change 0}}+{{end}}{{.Change}})</p>{{end}}
+                    {{.Trend}}
                 </div>
-            {{else}}
-                <div>No Performance findings.</div>
-            {{end}}
+                {{end}}
+                {{if .Hotspots}}
+                <div class="panel wide">
+                    <h3>Hotspots</h3>
+                    <p>Files that change often and are complex; red dots are high on both. Dot size grows with the number of findings.</p>
+                    {{.Hotspots}}
+                    <table>
+                        <tr><th>File</th><th class="num">Churn</th><th class="num">Complexity</th><th class="num">Findings</th><th class="num">Score</th></tr>
+                        {{range .TopHotspot}}
+                        <tr><td><a href="#file={{.Name}}">{{.Name}}</a>{{if .Hot}} 🔥{{end}}</td><td class="num">{{.Churn}}</td><td class="num">{{.Complexity}}</td><td class="num">{{.Findings}}</td><td class="num">{{.Score}}</td></tr>
+                        {{end}}
+                    </table>
+                </div>
+                {{end}}
+                <div class="panel">
+                    <h3>Packages</h3>
+                    <table>
+                        <tr><th>Package / file</th><th class="num">Lines</th><th class="num">Findings</th><th class="num">Per KLOC</th>{{if .Coverage}}<th class="num">Coverage</th>{{end}}<th class="num" title="Exported declarations with a doc comment">Docs</th><th class="num">Grade</th></tr>
+                        {{range .Overview.Packages}}{{$depth := .Depth}}
+                        <tbody>
+                            <tr class="pkg-row"><td style="{{indent .Depth}}"><span class="toggle">▸</span><span title="{{.Path}}">{{.Name}}</span></td><td class="num">{{.Lines}}</td><td class="num"><a href="#pkg={{.Path}}">{{.Findings}}</a></td><td class="num">{{printf "%.1f" .Density}}</td>{{if $.Coverage}}<td class="num">{{if .Statements}}{{printf "%.0f%%" .CoveragePercent}}{{else}}–{{end}}</td>{{end}}<td class="num">{{with .Docs}}{{if .Exported}}{{printf "%.0f%%" .Percent}}{{else}}–{{end}}{{end}}</td><td class="num" title="{{.Score.Value}} / 100"><span class="grade grade-{{.Score.Grade}}">{{.Score.Grade}}</span></td></tr>
+                            {{range .Files}}<tr class="file-row" hidden><td style="{{indent $depth 1}}">{{.Path}}</td><td class="num">{{.Lines}}</td><td class="num"><a href="#file={{.Path}}">{{.Findings}}</a></td><td class="num">{{printf "%.1f" .Density}}</td>{{if $.Coverage}}<td class="num">{{if .Statements}}{{printf "%.0f%%" .CoveragePercent}}{{else}}–{{end}}</td>{{end}}<td class="num">{{with .Docs}}{{if .Exported}}{{printf "%.0f%%" .Percent}}{{else}}–{{end}}{{end}}</td><td class="num" title="{{.Score.Value}} / 100"><span class="grade grade-{{.Score.Grade}}">{{.Score.Grade}}</span></td></tr>
+                            {{end}}
+                        </tbody>
+                        {{end}}
+                    </table>
+                </div>
+                <div class="panel">
+                    <h3>Severity heat map</h3>
+                    <table>
+                        <tr><th>Package</th><th class="num">Low</th><th class="num">Medium</th><th class="num">High</th><th class="num">Critical</th></tr>
+                        {{range .Overview.Packages}}{{if .Findings}}
+                        <tr><td><a href="#pkg={{.Path}}">{{.Path}}</a></td>{{$pkg := .}}{{range $sev := $.Severities}}{{$n := index $pkg.Severity $sev}}<td class="num" style="{{heat $n $.Overview.MaxSeverity}}">{{$n}}</td>{{end}}</tr>
+                        {{end}}{{end}}
+                    </table>
+                </div>
+                <div class="panel">
+                    <h3>Top {{len .Overview.TopFiles}} files</h3>
+                    <table>
+                        <tr><th>File</th><th class="num">Lines</th><th class="num">Findings</th><th class="num">Per KLOC</th></tr>
+                        {{range .Overview.TopFiles}}
+                        <tr><td><a href="#file={{.Path}}">{{.Path}}</a></td><td class="num">{{.Lines}}</td><td class="num">{{.Findings}}</td><td class="num">{{printf "%.1f" .Density}}</td></tr>
+                        {{end}}
+                    </table>
+                </div>
+                <div class="panel">
+                    <h3>Top {{len .Overview.TopRules}} rules</h3>
+                    <table>
+                        <tr><th>Rule</th><th>Category</th><th class="num">Findings</th></tr>
+                        {{range .Overview.TopRules}}
+                        <tr><td class="rule"><a href="#rule={{.Rule}}">{{.Rule}}</a></td><td>{{.Category}}</td><td class="num">{{.Count}}</td></tr>
+                        {{end}}
+                    </table>
+                </div>
+            </div>
         </div>
-        <div id="security-content" class="tab-content">
-            {{range .SecurityFindings}}
-                <div class="finding {{.Severity}}">
-                    <div class="file">{{.File}}:{{.Line}}</div>
+        <div class="tab-content" id="list">
+            <div class="filters">
+                <span>Severity:
+                    <label><input type="checkbox" name="sev" value="Low">Low</label>
+                    <label><input type="checkbox" name="sev" value="Medium">Medium</label>
+                    <label><input type="checkbox" name="sev" value="High">High</label>
+                    <label><input type="checkbox" name="sev" value="Critical">Critical</label>
+                </span>
+                <label>Rule:
+                    <select id="f-rule"><option value="">All rules</option>{{range .Rules}}<option value="{{.}}">{{.}}</option>{{end}}</select>
+                </label>
+                <label>Package:
+                    <select id="f-pkg"><option value="">All packages</option>{{range .Packages}}<option value="{{.}}">{{.}}</option>{{end}}</select>
+                </label>
+                {{if .Owners}}<label>Owner:
+                    <select id="f-owner"><option value="">All owners</option>{{range .Owners}}<option value="{{.}}">{{.}}</option>{{end}}</select>
+                </label>{{end}}
+                {{if .Blame}}<label>Introduced:
+                    <select id="f-age"><option value="">Any time</option><option value="recent">Last {{.RecentDays}} days</option><option value="legacy">Older</option></select>
+                </label>{{end}}
+                <input type="text" id="f-file" placeholder="File contains…">
+                <input type="text" id="f-q" placeholder="Search…">
+                <label>Group by:
+                    <select id="f-group"><option value="">None</option><option value="file">File</option><option value="rule">Rule</option>{{if .Owners}}<option value="owner">Owner</option>{{end}}</select>
+                </label>
+                <span class="count" id="shown"></span>
+            </div>
+            <div class="columns{{if .Blame}} with-blame{{end}}{{if .Coverage}} with-coverage{{end}}">
+                <span data-sort="severity">Severity</span>
+                <span data-sort="rule">Rule</span>
+                <span data-sort="file">File</span>
+                <span data-sort="line">Line</span>
+                {{if .Blame}}<span data-sort="age">Introduced</span>{{end}}
+                {{if .Coverage}}<span data-sort="coverage">Coverage</span>{{end}}
+            </div>
+            <div id="findings">
+                {{range .Findings}}
+                <div class="finding {{.Severity}}" data-index="{{.Index}}" data-category="{{.Category}}" data-severity="{{.Severity}}" data-rule="{{.Rule}}" data-file="{{.File}}" data-pkg="{{.Package}}" data-owner="{{.Owner}}" data-age="{{.Age}}" data-recent="{{.Recent}}" data-line="{{.Line}}" data-coverage="{{with .Coverage}}{{.}}{{else}}-1{{end}}" data-text="{{.Search}}">
+                    <div class="row{{if $.Blame}} with-blame{{end}}{{if $.Coverage}} with-coverage{{end}}">
+                        <span>{{.Severity}}</span>
+                        <span class="rule">{{.Rule}}</span>
+                        <span class="file">{{.File}}</span>
+                        <span>{{.Line}}</span>
+                        {{if $.Blame}}<span>{{with .Blame}}{{.Date.Format "2006-01-02"}}{{end}}{{if .Recent}} <span class="badge">new</span>{{end}}</span>{{end}}
+                        {{if $.Coverage}}<span>{{with .Coverage}}<span{{if eq . 0.0}} class="uncovered"{{end}}>{{printf "%.0f%%" .}}</span>{{else}}–{{end}}</span>{{end}}
+                    </div>
                     <div>{{.Message}}</div>
+                    {{if .Owners}}<div class="owner">👥 {{.Owner}}</div>{{end}}
+                    {{with .Blame}}<div class="owner">✍️ {{.Author}}{{if .Commit}} in {{shortHash .Commit}}{{end}} on {{.Date.Format "2006-01-02"}}</div>{{end}}
+                    {{if .Copies}}<div class="clones">{{range .Copies}}<div class="clone"><div class="clone-loc">{{.File}}:{{.Line}}–{{.EndLine}}</div><div class="snippet">{{range .Lines}}<span class="line"><span class="ln">{{.Number}}</span>{{.Code}}</span>{{end}}</div></div>{{end}}</div>
+                    {{else if .Snippet}}<div class="snippet">{{range .Snippet}}<span class="line{{if .Highlight}} hit{{end}}"><span class="ln">{{.Number}}</span>{{.Code}}</span>{{end}}</div>{{end}}
                     <div class="suggestion">💡 {{.Suggestion}}</div>
                 </div>
-            {{else}}
-                <div>No Security findings.</div>
-            {{end}}
+                {{end}}
+            </div>
+            <div class="empty" id="empty">No findings match the current filters.</div>
         </div>
+    <script>
+        var sevRank = { Low: 1, Medium: 2, High: 3, Critical: 4 };
+        var state = { view: "", cat: "", sev: [], rule: "", pkg: "", owner: "", age: "", file: "", q: "", sort: "", dir: "asc", group: "" };
+        var items = Array.prototype.slice.call(document.querySelectorAll("#findings .finding"));
+
+        function readHash() {
+            var params = new URLSearchParams(location.hash.slice(1));
+            for (var k in state) {
+                var v = params.get(k) || "";
+                state[k] = k === "sev" ? (v ? v.split(",") : []) : v;
+            }
+            if (!state.dir) state.dir = "asc";
+        }
+        function writeHash() {
+            var params = new URLSearchParams();
+            for (var k in state) {
+                var v = k === "sev" ? state.sev.join(",") : state[k];
+                if (v && !(k === "dir" && v === "asc")) params.set(k, v);
+            }
+            var hash = params.toString();
+            history.replaceState(null, "", hash ? "#" + hash : location.pathname + location.search);
+        }
+        function syncControls() {
+            document.querySelectorAll(".tab").forEach(function (t) {
+                var active = t.dataset.view ? t.dataset.view === state.view : !state.view && t.dataset.cat === state.cat;
+                t.classList.toggle("active", active);
+            });
+            document.getElementById("overview").style.display = state.view === "overview" ? "block" : "none";
+            document.getElementById("list").style.display = state.view === "overview" ? "none" : "block";
+            document.querySelectorAll("input[name=sev]").forEach(function (c) { c.checked = state.sev.indexOf(c.value) >= 0; });
+            document.getElementById("f-rule").value = state.rule;
+            document.getElementById("f-pkg").value = state.pkg;
+            if (document.getElementById("f-owner")) document.getElementById("f-owner").value = state.owner;
+            if (document.getElementById("f-age")) document.getElementById("f-age").value = state.age;
+            document.getElementById("f-file").value = state.file;
+            document.getElementById("f-q").value = state.q;
+            document.getElementById("f-group").value = state.group;
+            document.querySelectorAll(".columns span").forEach(function (c) {
+                c.classList.toggle("sorted", c.dataset.sort === state.sort);
+                c.classList.toggle("desc", c.dataset.sort === state.sort && state.dir === "desc");
+            });
+        }
+        function matches(el) {
+            var d = el.dataset;
+            return (!state.cat || d.category === state.cat) &&
+                (!state.sev.length || state.sev.indexOf(d.severity) >= 0) &&
+                (!state.rule || d.rule === state.rule) &&
+                (!state.pkg || d.pkg === state.pkg) &&
+                (!state.owner || d.owner.split(" ").indexOf(state.owner) >= 0) &&
+                (!state.age || (state.age === "recent") === (d.recent === "true")) &&
+                (!state.file || d.file.toLowerCase().indexOf(state.file.toLowerCase()) >= 0) &&
+                (!state.q || d.text.indexOf(state.q.toLowerCase()) >= 0);
+        }
+        function compare(a, b) {
+            var x = a.dataset, y = b.dataset, r = 0;
+            switch (state.sort) {
+            case "severity": r = sevRank[x.severity] - sevRank[y.severity]; break;
+            case "rule": r = x.rule.localeCompare(y.rule); break;
+            case "file": r = x.file.localeCompare(y.file) || x.line - y.line; break;
+            case "line": r = x.line - y.line; break;
+            case "age": r = x.age - y.age; break;
+            case "coverage": r = x.coverage - y.coverage; break;
+            }
+            if (state.dir === "desc") r = -r;
+            return r || x.index - y.index;
+        }
+        function render() {
+            var list = document.getElementById("findings");
+            var visible = items.filter(matches).sort(compare);
+            list.innerHTML = "";
+            if (!state.group) {
+                visible.forEach(function (el) { list.appendChild(el); });
+            } else {
+                var groups = {}, keys = [];
+                visible.forEach(function (el) {
+                    var key = el.dataset[state.group];
+                    if (!groups[key]) { groups[key] = []; keys.push(key); }
+                    groups[key].push(el);
+                });
+                keys.forEach(function (key) {
+                    var details = document.createElement("details");
+                    var summary = document.createElement("summary");
+                    details.className = "group";
+                    details.open = true;
+                    summary.textContent = key + " ";
+                    var count = document.createElement("span");
+                    count.className = "count";
+                    count.textContent = "(" + groups[key].length + ")";
+                    summary.appendChild(count);
+                    details.appendChild(summary);
+                    groups[key].forEach(function (el) { details.appendChild(el); });
+                    list.appendChild(details);
+                });
+            }
+            document.getElementById("empty").style.display = visible.length ? "none" : "block";
+            document.getElementById("shown").textContent = "Showing " + visible.length + " of " + items.length;
+            syncControls();
+            writeHash();
+        }
+        function update() {
+            state.sev = [];
+            document.querySelectorAll("input[name=sev]:checked").forEach(function (c) { state.sev.push(c.value); });
+            state.rule = document.getElementById("f-rule").value;
+            state.pkg = document.getElementById("f-pkg").value;
+            state.owner = document.getElementById("f-owner") ? document.getElementById("f-owner").value : "";
+            state.age = document.getElementById("f-age") ? document.getElementById("f-age").value : "";
+            state.file = document.getElementById("f-file").value;
+            state.q = document.getElementById("f-q").value;
+            state.group = document.getElementById("f-group").value;
+            render();
+        }
+
+        document.querySelectorAll(".tab").forEach(function (t) {
+            t.addEventListener("click", function () {
+                state.view = t.dataset.view || "";
+                if (!t.dataset.view) state.cat = t.dataset.cat;
+                render();
+            });
+        });
+        document.querySelectorAll(".pkg-row").forEach(function (row) {
+            row.addEventListener("click", function (e) {
+                if (e.target.tagName === "A") return;
+                var files = row.parentNode.querySelectorAll(".file-row");
+                var open = files.length && files[0].hidden;
+                files.forEach(function (f) { f.hidden = !open; });
+                row.querySelector(".toggle").textContent = open ? "▾" : "▸";
+            });
+        });
+        document.querySelectorAll(".columns span").forEach(function (c) {
+            c.addEventListener("click", function () {
+                if (state.sort === c.dataset.sort) {
+                    state.dir = state.dir === "asc" ? "desc" : "asc";
+                } else {
+                    state.sort = c.dataset.sort;
+                    state.dir = "asc";
+                }
+                render();
+            });
+        });
+        document.querySelectorAll(".filters input, .filters select").forEach(function (el) {
+            el.addEventListener(el.type === "text" ? "input" : "change", update);
+        });
+        window.addEventListener("hashchange", function () { readHash(); render(); });
+        readHash();
+        render();
+    </script>
     </body>
     </html>`
 
-	t := template.Must(template.New("report").Parse(tmpl))
+	funcs := template.FuncMap{
+		"heat":      heatColor,
+		"shortHash": vcs.ShortHash,
+		"indent": func(levels ...int) template.CSS {
+			depth := 0
+			for _, l := range levels {
+				depth += l
+			}
+			return template.CSS(fmt.Sprintf("padding-left: %dpx", 6+depth*16))
+		},
+	}
+	t := template.Must(template.New("report").Funcs(funcs).Parse(tmpl))
 	f, _ := os.Create("report.html")
 	defer f.Close()
 	t.Execute(f, data)
@@ -0,0 +1,36 @@
+package report
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gotech-hub/gocheck/analyzer"
+)
+
+func TestGenerateHTMLBlameCommit(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	date := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	findings := []analyzer.Finding{
+		{File: "a.go", Line: 1, Rule: "r", Category: "Clean", Severity: analyzer.Low, Blame: &analyzer.Blame{Commit: "0123456789abcdef", Author: "Ann", Date: date}},
+		{File: "b.go", Line: 1, Rule: "r", Category: "Clean", Severity: analyzer.Low, Blame: &analyzer.Blame{Commit: "abc", Author: "Bob", Date: date}},
+	}
+	GenerateHTML(findings, Metadata{})
+	data, err := os.ReadFile("report.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Ann in 01234567 on 2024-05-01", "Bob in abc on 2024-05-01"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("report.html does not contain %q", want)
+		}
+	}
+}
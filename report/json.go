@@ -3,14 +3,62 @@ package report
 import (
 	"encoding/json"
 	"os"
+	"time"
 
 	"github.com/gotech-hub/gocheck/analyzer"
+	"github.com/gotech-hub/gocheck/metrics"
 )
 
-func GenerateJSON(findings []analyzer.Finding) {
+// JSONReport is the document written to report.json. Older reports were a
+// bare array of findings, LoadJSON still reads both.
+type JSONReport struct {
+	Version     string             `json:"version"`
+	GeneratedAt time.Time          `json:"generated_at"`
+	Commit      string             `json:"commit,omitempty"`
+	Summary     JSONSummary        `json:"summary"`
+	Scores      Scores             `json:"scores"`
+	Docs        []JSONDocs         `json:"documentation"`
+	Findings    []analyzer.Finding `json:"findings"`
+}
+
+// JSONSummary counts the findings of a JSON report.
+type JSONSummary struct {
+	Total      int            `json:"total"`
+	BySeverity map[string]int `json:"by_severity"`
+	ByCategory map[string]int `json:"by_category"`
+}
+
+// JSONDocs is the documentation coverage of one package: how many of its
+// exported declarations have a doc comment.
+type JSONDocs struct {
+	Path string `json:"path"`
+	metrics.DocCount
+	Coverage float64 `json:"coverage"` // in percent
+}
+
+func GenerateJSON(findings []analyzer.Finding, meta Metadata) {
+	byCategory := map[string]int{}
+	for _, f := range findings {
+		byCategory[f.Category]++
+	}
+	if findings == nil {
+		findings = []analyzer.Finding{}
+	}
+	doc := JSONReport{
+		Version:     meta.Version,
+		GeneratedAt: time.Now(),
+		Commit:      meta.Commit,
+		Summary:     JSONSummary{Total: len(findings), BySeverity: severityStats(findings), ByCategory: byCategory},
+		Scores:      computeScores(findings, meta),
+		Findings:    findings,
+	}
+	for _, p := range buildOverview(findings, meta).Packages {
+		doc.Docs = append(doc.Docs, JSONDocs{Path: p.Path, DocCount: p.Docs, Coverage: p.Docs.Percent()})
+	}
+
 	f, _ := os.Create("report.json")
 	defer f.Close()
 	enc := json.NewEncoder(f)
 	enc.SetIndent("", "  ")
-	enc.Encode(findings)
+	enc.Encode(doc)
 }
@@ -0,0 +1,163 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gotech-hub/gocheck/analyzer"
+	"github.com/gotech-hub/gocheck/vcs"
+)
+
+// DefaultMarkdownMaxBytes keeps the report under GitHub's 65536 character
+// limit for pull-request comments, with room for a bot's own header.
+const DefaultMarkdownMaxBytes = 60000
+
+// MarkdownOptions configures GenerateMarkdown.
+type MarkdownOptions struct {
+	// RepoURL turns locations into links. It may contain {path}, {line} and
+	// {commit}, e.g. "https://github.com/org/repo/blob/{commit}/{path}#L{line}".
+	RepoURL string
+	// MaxBytes truncates the findings table so the report stays below this
+	// size. Zero means DefaultMarkdownMaxBytes.
+	MaxBytes int
+}
+
+// severityBadgeColors match the colors used by the HTML report.
+var severityBadgeColors = map[analyzer.Severity]string{
+	analyzer.Critical: "ff4d4f",
+	analyzer.High:     "fa8c16",
+	analyzer.Medium:   "fadb14",
+	analyzer.Low:      "95de64",
+}
+
+// GenerateMarkdown writes report.md, a compact summary meant to be posted as
+// a pull-request comment.
+func GenerateMarkdown(findings []analyzer.Finding, meta Metadata, opts MarkdownOptions) {
+	f, _ := os.Create("report.md")
+	defer f.Close()
+	f.WriteString(renderMarkdown(findings, meta, opts))
+}
+
+func renderMarkdown(findings []analyzer.Finding, meta Metadata, opts MarkdownOptions) string {
+	maxBytes := opts.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultMarkdownMaxBytes
+	}
+	stats := severityStats(findings)
+
+	var b strings.Builder
+	b.WriteString("## GoCheck report\n\n")
+	var badges []string
+	for i := len(severities) - 1; i >= 0; i-- {
+		s := severities[i]
+		badges = append(badges, fmt.Sprintf("![%s](https://img.shields.io/badge/%s-%d-%s)", s, s, stats[string(s)], severityBadgeColors[s]))
+	}
+	fmt.Fprintf(&b, "%s\n\n**%d** findings", strings.Join(badges, " "), len(findings))
+	if meta.Commit != "" {
+		fmt.Fprintf(&b, " at `%s`", vcs.ShortHash(meta.Commit))
+	}
+	b.WriteString(".")
+	if hasBlame(findings) {
+		days := meta.RecentDays
+		if days <= 0 {
+			days = DefaultRecentDays
+		}
+		recent, legacy := 0, 0
+		for _, f := range findings {
+			if isRecent(f, meta) {
+				recent++
+			} else if f.Blame != nil {
+				legacy++
+			}
+		}
+		fmt.Fprintf(&b, " %d introduced in the last %d days, %d legacy.", recent, days, legacy)
+	}
+	b.WriteString("\n\n")
+
+	if len(findings) == 0 {
+		return b.String()
+	}
+
+	b.WriteString("| Category | Critical | High | Medium | Low | Total |\n")
+	b.WriteString("| --- | ---: | ---: | ---: | ---: | ---: |\n")
+	for _, g := range groupByCategory(findings) {
+		s := severityStats(g.Findings)
+		fmt.Fprintf(&b, "| %s | %d | %d | %d | %d | %d |\n", g.Label, s["Critical"], s["High"], s["Medium"], s["Low"], len(g.Findings))
+	}
+
+	if hasOwners(findings) {
+		b.WriteString("\n| Owner | Critical | High | Medium | Low | Total |\n")
+		b.WriteString("| --- | ---: | ---: | ---: | ---: | ---: |\n")
+		for _, g := range groupFindings(findings, ownerOf) {
+			s := severityStats(g.Findings)
+			fmt.Fprintf(&b, "| %s | %d | %d | %d | %d | %d |\n", g.Key, s["Critical"], s["High"], s["Medium"], s["Low"], len(g.Findings))
+		}
+	}
+
+	top := make([]analyzer.Finding, len(findings))
+	copy(top, findings)
+	sort.SliceStable(top, func(i, j int) bool {
+		ri, rj := severityRank(top[i].Severity), severityRank(top[j].Severity)
+		if ri != rj {
+			return ri > rj
+		}
+		if top[i].File != top[j].File {
+			return top[i].File < top[j].File
+		}
+		return top[i].Line < top[j].Line
+	})
+
+	// Leave room for the summary line, the truncation note and the closing tag
+	budget := maxBytes - b.Len() - 300
+	var rows strings.Builder
+	rows.WriteString("| Severity | Rule | Location | Message |\n| --- | --- | --- | --- |\n")
+	shown := 0
+	for _, f := range top {
+		row := fmt.Sprintf("| %s | `%s` | %s | %s |\n", f.Severity, f.Rule, markdownLocation(f, meta, opts.RepoURL), markdownEscape(f.Message))
+		if rows.Len()+len(row) > budget {
+			break
+		}
+		rows.WriteString(row)
+		shown++
+	}
+
+	fmt.Fprintf(&b, "\n<details>\n<summary>Top findings (%d of %d)</summary>\n\n", shown, len(top))
+	b.WriteString(rows.String())
+	if shown < len(top) {
+		fmt.Fprintf(&b, "\n_%d more findings not shown to keep this comment under %d bytes; see the full HTML or JSON report._\n", len(top)-shown, maxBytes)
+	}
+	b.WriteString("\n</details>\n")
+	return b.String()
+}
+
+// markdownLocation renders file:line, linked through the repository URL
+// pattern when one is configured. {path} is relative to the top of the git
+// repository, which is not the scanned directory with --path ./pkg.
+func markdownLocation(f analyzer.Finding, meta Metadata, pattern string) string {
+	text := fmt.Sprintf("`%s:%d`", relPath(meta.Root, f.File), f.Line)
+	if pattern == "" {
+		return text
+	}
+	root := meta.RepoRoot
+	if root == "" {
+		root = meta.Root
+	}
+	url := strings.NewReplacer(
+		"{path}", relPath(root, f.File),
+		"{line}", strconv.Itoa(f.Line),
+		"{commit}", meta.Commit,
+	).Replace(pattern)
+	return fmt.Sprintf("[%s](%s)", text, url)
+}
+
+func severityRank(s analyzer.Severity) int {
+	for i, sev := range severities {
+		if sev == s {
+			return i + 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,24 @@
+package report
+
+import (
+	"github.com/gotech-hub/gocheck/coverage"
+	"github.com/gotech-hub/gocheck/history"
+	"github.com/gotech-hub/gocheck/hotspot"
+	"github.com/gotech-hub/gocheck/metrics"
+)
+
+// Metadata describes the scan a report was generated from.
+type Metadata struct {
+	Version    string                      // gocheck version that produced the report
+	Root       string                      // directory that was scanned
+	Commit     string                      // commit checked out in Root, if it is a git repository
+	RepoRoot   string                      // top-level directory of the git repository containing Root, "" outside git
+	Lines      map[string]int              // physical line count per scanned file
+	History    []history.Run               // previous runs, oldest first, including this one
+	RecentDays int                         // blamed findings younger than this many days count as recent, not legacy
+	Weights    Weights                     // maintainability score weights, the zero value means DefaultWeights
+	Hotspots   []hotspot.Hotspot           // files ranked by churn and complexity, highest first; nil unless --hotspots
+	Coverage   *coverage.Profile           // test coverage from --coverprofile, nil without it
+	Docs       map[string]metrics.DocCount // exported and documented declarations per scanned file
+	Code       map[string]metrics.FileCode // comment lines and function complexity per scanned file, used by the score
+}
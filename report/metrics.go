@@ -0,0 +1,71 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/gotech-hub/gocheck/metrics"
+)
+
+// WriteMetricsText writes the package metrics as an aligned table followed by
+// a total row.
+func WriteMetricsText(w io.Writer, pkgs []metrics.Package) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "Package\tFiles\tLines\tLogical\tComments\tFuncs\tTypes\tIfaces\tAvg CC\tMax CC\tFan-in\tFan-out\tTest ratio\tDocs\t")
+	for _, p := range append(pkgs, metrics.Total(pkgs)) {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%.0f%%\t%d\t%d\t%d\t%.1f\t%d\t%d\t%d\t%.2f\t%.0f%%\t\n",
+			p.Path, p.Files, p.PhysicalLines, p.LogicalLines, p.CommentRatio*100, p.Funcs, p.Types, p.Interfaces,
+			p.AvgComplexity, p.MaxComplexity, p.FanIn, p.FanOut, p.TestRatio, p.DocCoverage)
+	}
+	tw.Flush()
+}
+
+// WriteMetricsJSON writes the package metrics and their total as JSON.
+func WriteMetricsJSON(w io.Writer, pkgs []metrics.Package) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Packages []metrics.Package `json:"packages"`
+		Total    metrics.Package   `json:"total"`
+	}{pkgs, metrics.Total(pkgs)})
+}
+
+// WriteMetricsCSV writes one CSV row per package.
+func WriteMetricsCSV(w io.Writer, pkgs []metrics.Package) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"package", "import_path", "files", "test_files", "physical_lines", "logical_lines", "comment_lines", "comment_ratio",
+		"funcs", "types", "interfaces", "avg_complexity", "max_complexity", "max_func", "fan_in", "fan_out", "imports", "test_lines", "test_ratio",
+		"exported", "documented", "doc_coverage"})
+	for _, p := range pkgs {
+		cw.Write([]string{
+			p.Path,
+			p.ImportPath,
+			strconv.Itoa(p.Files),
+			strconv.Itoa(p.TestFiles),
+			strconv.Itoa(p.PhysicalLines),
+			strconv.Itoa(p.LogicalLines),
+			strconv.Itoa(p.CommentLines),
+			strconv.FormatFloat(p.CommentRatio, 'f', 3, 64),
+			strconv.Itoa(p.Funcs),
+			strconv.Itoa(p.Types),
+			strconv.Itoa(p.Interfaces),
+			strconv.FormatFloat(p.AvgComplexity, 'f', 2, 64),
+			strconv.Itoa(p.MaxComplexity),
+			p.MaxFunc,
+			strconv.Itoa(p.FanIn),
+			strconv.Itoa(p.FanOut),
+			strconv.Itoa(p.Imports),
+			strconv.Itoa(p.TestLines),
+			strconv.FormatFloat(p.TestRatio, 'f', 3, 64),
+			strconv.Itoa(p.Exported),
+			strconv.Itoa(p.Documented),
+			strconv.FormatFloat(p.DocCoverage, 'f', 1, 64),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,218 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+
+	"github.com/gotech-hub/gocheck/analyzer"
+	"github.com/gotech-hub/gocheck/metrics"
+)
+
+const overviewTopN = 10
+
+// FileStats aggregates the findings reported for one file.
+type FileStats struct {
+	Path     string
+	Lines    int
+	Findings int
+	Severity map[string]int
+	Score    Score
+	// statements in the coverage profile and how many of them ran, both 0
+	// without --coverprofile or when the file is not in the profile
+	Statements int
+	Covered    int
+	Docs       metrics.DocCount
+}
+
+// Density returns the number of findings per thousand lines of code.
+func (s FileStats) Density() float64 {
+	return density(s.Findings, s.Lines)
+}
+
+// CoveragePercent returns the statement coverage of the file.
+func (s FileStats) CoveragePercent() float64 {
+	return percent(s.Covered, s.Statements)
+}
+
+// PackageStats aggregates the findings reported for the files of one
+// package directory.
+type PackageStats struct {
+	Path       string
+	Depth      int // number of directories between the scan root and the package
+	Lines      int
+	Findings   int
+	Severity   map[string]int
+	Score      Score
+	Statements int
+	Covered    int
+	Docs       metrics.DocCount
+	Files      []FileStats
+}
+
+// Name is the last element of the package path, used in the package tree.
+func (s PackageStats) Name() string {
+	return s.Path[strings.LastIndex(s.Path, "/")+1:]
+}
+
+// Density returns the number of findings per thousand lines of code.
+func (s PackageStats) Density() float64 {
+	return density(s.Findings, s.Lines)
+}
+
+// CoveragePercent returns the statement coverage of the package.
+func (s PackageStats) CoveragePercent() float64 {
+	return percent(s.Covered, s.Statements)
+}
+
+// RuleStats counts how often a rule was reported.
+type RuleStats struct {
+	Rule     string
+	Category string
+	Count    int
+	Severity map[analyzer.Severity]int // findings of the rule per severity
+}
+
+// Overview holds the dashboard data shown on the HTML report overview page.
+type Overview struct {
+	Packages    []PackageStats // sorted by path so they render as a tree
+	TopFiles    []FileStats
+	TopRules    []RuleStats
+	MaxSeverity int   // largest per-package severity count, scales the heat map
+	Score       Score // maintainability score of the whole project
+}
+
+func density(findings, lines int) float64 {
+	if lines == 0 {
+		return 0
+	}
+	return float64(findings) * 1000 / float64(lines)
+}
+
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) * 100 / float64(total)
+}
+
+// buildOverview aggregates findings per file, package and rule. Every scanned
+// file in meta.Lines is included, so clean packages still show up in the tree.
+func buildOverview(findings []analyzer.Finding, meta Metadata) Overview {
+	files := map[string]*FileStats{}
+	fileStats := func(path string) *FileStats {
+		s, ok := files[path]
+		if !ok {
+			s = &FileStats{Path: path, Severity: map[string]int{}}
+			files[path] = s
+		}
+		return s
+	}
+	for file, n := range meta.Lines {
+		s := fileStats(relPath(meta.Root, file))
+		s.Lines = n
+		if meta.Coverage != nil {
+			s.Covered, s.Statements, _ = meta.Coverage.File(file)
+		}
+		s.Docs = meta.Docs[file]
+	}
+
+	for _, f := range findings {
+		s := fileStats(relPath(meta.Root, f.File))
+		s.Findings++
+		s.Severity[string(f.Severity)]++
+	}
+
+	scores := computeScores(findings, meta)
+	fileScores, pkgScores := byPath(scores.Files), byPath(scores.Packages)
+
+	ov := Overview{Score: scores.Project}
+	pkgs := map[string]*PackageStats{}
+	var allFiles []FileStats
+	for _, s := range files {
+		s.Score = fileScores[s.Path]
+		allFiles = append(allFiles, *s)
+		path := packageOf(s.Path)
+		p, ok := pkgs[path]
+		if !ok {
+			p = &PackageStats{Path: path, Severity: map[string]int{}, Score: pkgScores[path]}
+			if path != "." {
+				p.Depth = strings.Count(path, "/")
+			}
+			pkgs[path] = p
+		}
+		p.Lines += s.Lines
+		p.Findings += s.Findings
+		p.Statements += s.Statements
+		p.Covered += s.Covered
+		p.Docs = p.Docs.Add(s.Docs)
+		for sev, n := range s.Severity {
+			p.Severity[sev] += n
+		}
+		p.Files = append(p.Files, *s)
+	}
+	for _, p := range pkgs {
+		sort.Slice(p.Files, func(i, j int) bool { return p.Files[i].Path < p.Files[j].Path })
+		for _, n := range p.Severity {
+			ov.MaxSeverity = max(ov.MaxSeverity, n)
+		}
+		ov.Packages = append(ov.Packages, *p)
+	}
+	sort.Slice(ov.Packages, func(i, j int) bool { return ov.Packages[i].Path < ov.Packages[j].Path })
+
+	sort.Slice(allFiles, func(i, j int) bool {
+		if allFiles[i].Findings != allFiles[j].Findings {
+			return allFiles[i].Findings > allFiles[j].Findings
+		}
+		if allFiles[i].Density() != allFiles[j].Density() {
+			return allFiles[i].Density() > allFiles[j].Density()
+		}
+		return allFiles[i].Path < allFiles[j].Path
+	})
+	for _, s := range allFiles {
+		if s.Findings == 0 || len(ov.TopFiles) == overviewTopN {
+			break
+		}
+		ov.TopFiles = append(ov.TopFiles, s)
+	}
+
+	ov.TopRules = ruleStats(findings)
+	if len(ov.TopRules) > overviewTopN {
+		ov.TopRules = ov.TopRules[:overviewTopN]
+	}
+	return ov
+}
+
+// ruleStats counts findings per rule, most frequent rule first.
+func ruleStats(findings []analyzer.Finding) []RuleStats {
+	index := map[string]int{}
+	var rules []RuleStats
+	for _, f := range findings {
+		i, ok := index[f.Rule]
+		if !ok {
+			i = len(rules)
+			index[f.Rule] = i
+			rules = append(rules, RuleStats{Rule: f.Rule, Category: f.Category, Severity: map[analyzer.Severity]int{}})
+		}
+		rules[i].Count++
+		rules[i].Severity[f.Severity]++
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].Count != rules[j].Count {
+			return rules[i].Count > rules[j].Count
+		}
+		return rules[i].Rule < rules[j].Rule
+	})
+	return rules
+}
+
+// heatColor returns the background of a heat map cell holding n findings,
+// from transparent for zero up to solid red for the largest count.
+func heatColor(n, maxCount int) template.CSS {
+	if n == 0 || maxCount == 0 {
+		return "background: transparent"
+	}
+	alpha := 0.15 + 0.85*float64(n)/float64(maxCount)
+	return template.CSS(fmt.Sprintf("background: rgba(255, 77, 79, %.2f)", alpha))
+}
@@ -0,0 +1,241 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gotech-hub/gocheck/analyzer"
+	"github.com/gotech-hub/gocheck/metrics"
+)
+
+// minScoredLines keeps tiny files from being graded F for a single finding:
+// every file is scored as if it had at least this many lines.
+const minScoredLines = 100
+
+// Weights sets how much findings and code metrics cost in the
+// maintainability score. A finding costs its severity weight multiplied by
+// its category weight; categories without a weight count 1. Every point of
+// cyclomatic complexity of a function above freeComplexity costs Complexity,
+// and every comment line missing to reach minCommentRatio costs Comments.
+type Weights struct {
+	Severity   map[string]float64 `json:"severity"`
+	Category   map[string]float64 `json:"category,omitempty"`
+	Complexity float64            `json:"complexity"`
+	Comments   float64            `json:"comments"`
+}
+
+// freeComplexity is the cyclomatic complexity of a function that costs
+// nothing in the score, well below the complexity rule's threshold.
+const freeComplexity = 5
+
+// minCommentRatio is the share of comment lines below which a file is
+// charged for the missing comments.
+const minCommentRatio = 0.1
+
+// DefaultWeights returns the weights used unless --score-weights is given.
+// A function of complexity 15 costs as much as a High finding, a file
+// without comments as much as a Low finding per 100 lines.
+func DefaultWeights() Weights {
+	return Weights{
+		Severity:   map[string]float64{"Low": 1, "Medium": 3, "High": 7, "Critical": 15},
+		Category:   map[string]float64{},
+		Complexity: 0.7,
+		Comments:   0.1,
+	}
+}
+
+// ParseWeights reads weights written as "Key=value,Key=value", where a key
+// is a severity, a built-in category name, Complexity or Comments, matched
+// ignoring case, on top of the defaults. For example "Critical=20,Security=2"
+// makes critical findings and security findings cost more, and
+// "Complexity=0,Comments=0" scores the findings alone. Unknown keys are an
+// error.
+func ParseWeights(s string) (Weights, error) {
+	w := DefaultWeights()
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return w, fmt.Errorf("invalid weight %q, expected Key=value", part)
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || v < 0 {
+			return w, fmt.Errorf("invalid weight %q, expected a non-negative number", part)
+		}
+		key = strings.TrimSpace(key)
+		if strings.EqualFold(key, "Complexity") {
+			w.Complexity = v
+		} else if strings.EqualFold(key, "Comments") {
+			w.Comments = v
+		} else if severity, ok := lookupName(w.Severity, key); ok {
+			w.Severity[severity] = v
+		} else if category, ok := lookupName(categoryOrder, key); ok {
+			w.Category[category] = v
+		} else {
+			return w, fmt.Errorf("unknown weight key %q, expected a severity (Low, Medium, High, Critical), a category (%s), Complexity or Comments", key, strings.Join(knownCategories(), ", "))
+		}
+	}
+	return w, nil
+}
+
+// lookupName returns the key of m equal to name ignoring case, so
+// "critical=20" weighs Critical findings.
+func lookupName[V any](m map[string]V, name string) (string, bool) {
+	for k := range m {
+		if strings.EqualFold(k, name) {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// knownCategories returns the built-in categories in their tab order.
+func knownCategories() []string {
+	names := make([]string, 0, len(categoryOrder))
+	for name := range categoryOrder {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return categoryOrder[names[i]] < categoryOrder[names[j]] })
+	return names
+}
+
+func (w Weights) cost(f analyzer.Finding) float64 {
+	cost := w.Severity[string(f.Severity)]
+	if c, ok := w.Category[f.Category]; ok {
+		cost *= c
+	}
+	return cost
+}
+
+// codeCost is the cost of the complexity and the missing comments of a
+// file of the given physical lines.
+func (w Weights) codeCost(code metrics.FileCode, lines int) (complexity, comments float64) {
+	for _, cc := range code.Complexity {
+		if cc > freeComplexity {
+			complexity += w.Complexity * float64(cc-freeComplexity)
+		}
+	}
+	if missing := minCommentRatio*float64(lines) - float64(code.CommentLines); missing > 0 {
+		comments = w.Comments * missing
+	}
+	return complexity, comments
+}
+
+// Score is the maintainability of a file, package or project, from 100
+// (no findings, simple and commented code) down towards 0, with a letter
+// grade.
+type Score struct {
+	Path       string  `json:"path,omitempty"`
+	Value      float64 `json:"score"`
+	Grade      string  `json:"grade"`
+	Lines      int     `json:"lines"`
+	Penalty    float64 `json:"penalty"`            // sum of the three costs below
+	Findings   float64 `json:"findings_penalty"`   // weighted cost of the findings
+	Complexity float64 `json:"complexity_penalty"` // cost of function complexity above freeComplexity
+	Comments   float64 `json:"comments_penalty"`   // cost of comment lines missing to minCommentRatio
+}
+
+// add sums the penalties of o into s.
+func (s *Score) add(o Score) {
+	s.Findings += o.Findings
+	s.Complexity += o.Complexity
+	s.Comments += o.Comments
+	s.Lines += o.Lines
+}
+
+// Scores holds the scores of the whole project and of each package and file.
+type Scores struct {
+	Project  Score   `json:"project"`
+	Packages []Score `json:"packages"`
+	Files    []Score `json:"files"`
+}
+
+// scoreScale is the penalty per KLOC that halves the score.
+const scoreScale = 50
+
+// scored derives the score of s from its weighted penalty per thousand
+// lines: 100 * scale / (scale + penalty per KLOC). With the default weights
+// one High finding per KLOC still grades B, five grade F.
+func (s Score) scored() Score {
+	s.Penalty = s.Findings + s.Complexity + s.Comments
+	kloc := float64(max(s.Lines, minScoredLines)) / 1000
+	value := 100 * scoreScale / (scoreScale + s.Penalty/kloc)
+	s.Value, s.Grade = math.Round(value*10)/10, grade(value)
+	s.Penalty, s.Findings = math.Round(s.Penalty*100)/100, math.Round(s.Findings*100)/100
+	s.Complexity, s.Comments = math.Round(s.Complexity*100)/100, math.Round(s.Comments*100)/100
+	return s
+}
+
+func grade(value float64) string {
+	switch {
+	case value >= 90:
+		return "A"
+	case value >= 80:
+		return "B"
+	case value >= 70:
+		return "C"
+	case value >= 60:
+		return "D"
+	}
+	return "F"
+}
+
+// computeScores scores every scanned file, every package and the project.
+func computeScores(findings []analyzer.Finding, meta Metadata) Scores {
+	weights := meta.Weights
+	if weights.Severity == nil {
+		weights = DefaultWeights()
+	}
+	files := map[string]*Score{}
+	file := func(path string) *Score {
+		path = relPath(meta.Root, path)
+		if files[path] == nil {
+			files[path] = &Score{Path: path}
+		}
+		return files[path]
+	}
+	for path, n := range meta.Lines {
+		s := file(path)
+		s.Lines = n
+		if code, ok := meta.Code[path]; ok {
+			s.Complexity, s.Comments = weights.codeCost(code, n)
+		}
+	}
+	for _, f := range findings {
+		file(f.File).Findings += weights.cost(f)
+	}
+
+	var s Scores
+	pkgs := map[string]*Score{}
+	for _, fs := range files {
+		s.Files = append(s.Files, fs.scored())
+		pkg := packageOf(fs.Path)
+		if pkgs[pkg] == nil {
+			pkgs[pkg] = &Score{Path: pkg}
+		}
+		pkgs[pkg].add(*fs)
+		s.Project.add(*fs)
+	}
+	for _, ps := range pkgs {
+		s.Packages = append(s.Packages, ps.scored())
+	}
+	sort.Slice(s.Files, func(i, j int) bool { return s.Files[i].Path < s.Files[j].Path })
+	sort.Slice(s.Packages, func(i, j int) bool { return s.Packages[i].Path < s.Packages[j].Path })
+	s.Project = s.Project.scored()
+	return s
+}
+
+// byPath indexes scores by path for the templates.
+func byPath(scores []Score) map[string]Score {
+	m := make(map[string]Score, len(scores))
+	for _, s := range scores {
+		m[s.Path] = s
+	}
+	return m
+}
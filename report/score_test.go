@@ -0,0 +1,82 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/gotech-hub/gocheck/analyzer"
+	"github.com/gotech-hub/gocheck/metrics"
+)
+
+func TestParseWeights(t *testing.T) {
+	tests := []struct {
+		in       string
+		severity map[string]float64
+		category map[string]float64
+		err      bool
+	}{
+		{in: "", severity: map[string]float64{"Critical": 15}},
+		{in: "Critical=20,Security=2", severity: map[string]float64{"Critical": 20}, category: map[string]float64{"Security": 2}},
+		{in: "critical=20, HIGH=9", severity: map[string]float64{"Critical": 20, "High": 9}},
+		{in: "security=2", category: map[string]float64{"Security": 2}},
+		{in: "complexity=0,Comments=2"},
+		{in: "Style=2", err: true},
+		{in: "Critical", err: true},
+		{in: "Critical=-1", err: true},
+	}
+	for _, tt := range tests {
+		w, err := ParseWeights(tt.in)
+		if (err != nil) != tt.err {
+			t.Errorf("ParseWeights(%q) error = %v, want error %v", tt.in, err, tt.err)
+			continue
+		}
+		for k, v := range tt.severity {
+			if w.Severity[k] != v {
+				t.Errorf("ParseWeights(%q).Severity[%s] = %v, want %v", tt.in, k, w.Severity[k], v)
+			}
+		}
+		for k, v := range tt.category {
+			if w.Category[k] != v {
+				t.Errorf("ParseWeights(%q).Category[%s] = %v, want %v", tt.in, k, w.Category[k], v)
+			}
+		}
+		if !tt.err && len(w.Severity) != 4 {
+			t.Errorf("ParseWeights(%q) has severities %v, want only the four known ones", tt.in, w.Severity)
+		}
+	}
+}
+
+func TestComputeScores(t *testing.T) {
+	meta := Metadata{
+		Root:  "/src",
+		Lines: map[string]int{"/src/a/a.go": 200, "/src/a/b.go": 100, "/src/c/c.go": 100},
+		Code: map[string]metrics.FileCode{
+			"/src/a/a.go": {CommentLines: 20, Complexity: []int{3, 15}}, // 10 points above freeComplexity
+			"/src/a/b.go": {CommentLines: 4},                            // 6 comment lines missing
+			"/src/c/c.go": {CommentLines: 10, Complexity: []int{5}},
+		},
+	}
+	findings := []analyzer.Finding{{File: "/src/a/a.go", Severity: analyzer.High}}
+	s := computeScores(findings, meta)
+
+	files := byPath(s.Files)
+	if got := files["a/a.go"]; got.Findings != 7 || got.Complexity != 7 || got.Comments != 0 || got.Penalty != 14 {
+		t.Errorf("a/a.go score = %+v, want findings 7, complexity 7, comments 0", got)
+	}
+	if got := files["a/b.go"]; got.Comments != 0.6 || got.Penalty != 0.6 {
+		t.Errorf("a/b.go score = %+v, want comments 0.6", got)
+	}
+	if got := files["c/c.go"]; got.Penalty != 0 || got.Value != 100 || got.Grade != "A" {
+		t.Errorf("c/c.go score = %+v, want 100 A", got)
+	}
+	if got := byPath(s.Packages)["a"]; got.Lines != 300 || got.Penalty != 14.6 {
+		t.Errorf("package a score = %+v, want 300 lines and penalty 14.6", got)
+	}
+	if s.Project.Lines != 400 || s.Project.Penalty != 14.6 {
+		t.Errorf("project score = %+v, want 400 lines and penalty 14.6", s.Project)
+	}
+
+	meta.Weights, _ = ParseWeights("Complexity=0,Comments=0")
+	if got := byPath(computeScores(findings, meta).Files)["a/a.go"]; got.Penalty != 7 {
+		t.Errorf("a/a.go penalty without code weights = %v, want 7", got.Penalty)
+	}
+}
@@ -0,0 +1,172 @@
+package report
+
+import (
+	"go/token"
+	"html"
+	"html/template"
+	"os"
+	"strings"
+
+	goscanner "go/scanner"
+
+	"github.com/gotech-hub/gocheck/analyzer"
+)
+
+const (
+	snippetContext = 3  // lines shown before and after the offending range
+	maxSnippetSpan = 12 // longest offending range rendered in full
+	maxCloneSpan   = 40 // longest copy of duplicated code rendered in the side-by-side view
+)
+
+// SnippetLine is one line of highlighted source shown next to a finding.
+type SnippetLine struct {
+	Number    int
+	Code      template.HTML
+	Highlight bool
+}
+
+// CloneSnippet is the source of one copy of duplicated code.
+type CloneSnippet struct {
+	analyzer.Location
+	Lines []SnippetLine
+}
+
+// sourceCache keeps the highlighted lines of every file read while rendering
+// a report, so files with many findings are only read and tokenized once.
+type sourceCache map[string][]template.HTML
+
+func (c sourceCache) lines(file string) []template.HTML {
+	if lines, ok := c[file]; ok {
+		return lines
+	}
+	src, err := os.ReadFile(file)
+	if err != nil {
+		c[file] = nil
+		return nil
+	}
+	lines := highlightGo(src)
+	c[file] = lines
+	return lines
+}
+
+// snippet returns the source around f with the offending lines marked. It
+// returns nil if the file cannot be read or the line is out of range.
+func (c sourceCache) snippet(f analyzer.Finding) []SnippetLine {
+	lines := c.lines(f.File)
+	if f.Line < 1 || f.Line > len(lines) {
+		return nil
+	}
+	end := f.Line
+	if f.EndLine > end {
+		end = f.EndLine
+	}
+	if end-f.Line > maxSnippetSpan {
+		end = f.Line + maxSnippetSpan
+	}
+	return lineRange(lines, f.Line-snippetContext, end+snippetContext, f.Line, end)
+}
+
+// clones returns the copies of duplicated code reported by f, its own
+// location first, for the side-by-side view. It returns nil for findings
+// without related locations.
+func (c sourceCache) clones(f analyzer.Finding) []CloneSnippet {
+	if len(f.Related) == 0 {
+		return nil
+	}
+	var copies []CloneSnippet
+	for _, loc := range append([]analyzer.Location{{File: f.File, Line: f.Line, EndLine: f.EndLine}}, f.Related...) {
+		end := min(loc.EndLine, loc.Line+maxCloneSpan-1)
+		copies = append(copies, CloneSnippet{Location: loc, Lines: lineRange(c.lines(loc.File), loc.Line, end, 0, 0)})
+	}
+	return copies
+}
+
+// lineRange returns lines from..to, clamped to the file, with the lines
+// hitFrom..hitTo highlighted.
+func lineRange(lines []template.HTML, from, to, hitFrom, hitTo int) []SnippetLine {
+	from = max(1, from)
+	to = min(len(lines), to)
+	var snippet []SnippetLine
+	for n := from; n <= to; n++ {
+		snippet = append(snippet, SnippetLine{
+			Number:    n,
+			Code:      lines[n-1],
+			Highlight: n >= hitFrom && n <= hitTo,
+		})
+	}
+	return snippet
+}
+
+// highlightGo tokenizes src and returns it as escaped HTML split into lines,
+// with keywords, literals, comments and predeclared identifiers wrapped in
+// spans. Spans never cross a line break so every line can be rendered alone.
+func highlightGo(src []byte) []template.HTML {
+	var s goscanner.Scanner
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	s.Init(file, src, nil, goscanner.ScanComments)
+
+	var b strings.Builder
+	last := 0
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		class := tokenClass(tok, lit)
+		if class == "" || lit == "" {
+			continue
+		}
+		off := file.Offset(pos)
+		end := off + len(lit)
+		if off < last || end > len(src) {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(src[last:off])))
+		for i, part := range strings.Split(string(src[off:end]), "\n") {
+			if i > 0 {
+				b.WriteByte('\n')
+			}
+			if part != "" {
+				b.WriteString(`<span class="hl-` + class + `">` + html.EscapeString(part) + `</span>`)
+			}
+		}
+		last = end
+	}
+	b.WriteString(html.EscapeString(string(src[last:])))
+
+	raw := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	lines := make([]template.HTML, len(raw))
+	for i, l := range raw {
+		lines[i] = template.HTML(strings.TrimSuffix(l, "\r"))
+	}
+	return lines
+}
+
+func tokenClass(tok token.Token, lit string) string {
+	switch {
+	case tok.IsKeyword():
+		return "kw"
+	case tok == token.STRING || tok == token.CHAR:
+		return "str"
+	case tok == token.INT || tok == token.FLOAT || tok == token.IMAG:
+		return "num"
+	case tok == token.COMMENT:
+		return "com"
+	case tok == token.IDENT && predeclared[lit]:
+		return "bi"
+	}
+	return ""
+}
+
+var predeclared = map[string]bool{
+	"bool": true, "byte": true, "complex64": true, "complex128": true, "error": true,
+	"float32": true, "float64": true, "int": true, "int8": true, "int16": true,
+	"int32": true, "int64": true, "rune": true, "string": true, "uint": true,
+	"uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+	"any": true, "comparable": true, "true": true, "false": true, "iota": true,
+	"nil": true, "append": true, "cap": true, "clear": true, "close": true,
+	"complex": true, "copy": true, "delete": true, "imag": true, "len": true,
+	"make": true, "max": true, "min": true, "new": true, "panic": true,
+	"print": true, "println": true, "real": true, "recover": true,
+}
@@ -0,0 +1,175 @@
+package report
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/gotech-hub/gocheck/analyzer"
+	"github.com/gotech-hub/gocheck/vcs"
+)
+
+// TemplateData is the data model passed to custom report templates given
+// with --template.
+type TemplateData struct {
+	Findings    []analyzer.Finding // every finding, in analysis order
+	Stats       map[string]int     // finding count per severity ("Low" … "Critical")
+	Total       int                // len(Findings)
+	Categories  []CategoryGroup    // findings per category: .Name, .Label, .Findings
+	Rules       []RuleStats        // every reported rule: .Rule, .Category, .Count, .Severity, most frequent first
+	Scores      Scores             // maintainability scores: .Project, .Packages, .Files with .Value and .Grade
+	Metadata    Metadata           // .Version, .Root, .Commit, .Lines, .History
+	GeneratedAt time.Time
+}
+
+// templateFuncs are the helpers available to custom templates:
+//
+//	groupBy FIELD FINDINGS   groups by "file", "package", "rule", "category", "severity" or "owner"
+//	severityColor SEVERITY   hex color used for the severity in the HTML report
+//	relPath FILE             FILE relative to the scanned directory
+//	shortHash COMMIT         COMMIT abbreviated for display
+func templateFuncs(meta Metadata) map[string]any {
+	return map[string]any{
+		"groupBy": groupBy(meta),
+		"severityColor": func(s analyzer.Severity) string {
+			return "#" + severityBadgeColors[s]
+		},
+		"relPath": func(file string) string {
+			return relPath(meta.Root, file)
+		},
+		"shortHash": vcs.ShortHash,
+	}
+}
+
+func groupBy(meta Metadata) func(string, []analyzer.Finding) ([]FindingGroup, error) {
+	return func(field string, findings []analyzer.Finding) ([]FindingGroup, error) {
+		var key func(analyzer.Finding) string
+		switch field {
+		case "file":
+			key = func(f analyzer.Finding) string { return relPath(meta.Root, f.File) }
+		case "package":
+			key = func(f analyzer.Finding) string { return packageOf(relPath(meta.Root, f.File)) }
+		case "rule":
+			key = func(f analyzer.Finding) string { return f.Rule }
+		case "category":
+			key = func(f analyzer.Finding) string { return f.Category }
+		case "severity":
+			key = func(f analyzer.Finding) string { return string(f.Severity) }
+		case "owner":
+			key = ownerOf
+		default:
+			return nil, fmt.Errorf("groupBy: unknown field %q", field)
+		}
+		groups := groupFindings(findings, key)
+		if field == "severity" {
+			sort.SliceStable(groups, func(i, j int) bool {
+				return severityRank(analyzer.Severity(groups[i].Key)) > severityRank(analyzer.Severity(groups[j].Key))
+			})
+		}
+		return groups, nil
+	}
+}
+
+// reservedOutputs are the files written by the built-in reports, a template
+// never renders over them by default.
+var reservedOutputs = map[string]bool{
+	"report.html": true, "report.json": true, "report.md": true, "report.csv": true, "report-rules.csv": true,
+}
+
+// TemplateOutput returns the default output file for a template: the
+// template's file name without its .tmpl extension, e.g.
+// "compliance.html.tmpl" renders to "compliance.html". It fails when that
+// name is the template itself, e.g. "summary.md" run from its directory, or
+// a built-in report, then --template-out has to be given.
+func TemplateOutput(templatePath string) (string, error) {
+	name := templateName(templatePath)
+	if reservedOutputs[name] {
+		return "", fmt.Errorf("default output %s would overwrite the built-in report, use --template-out", name)
+	}
+	if SameFile(name, templatePath) {
+		return "", fmt.Errorf("default output %s would overwrite the template, use --template-out", name)
+	}
+	return name, nil
+}
+
+// SameFile reports whether the paths a and b name the same file.
+func SameFile(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
+// templateName is the template's file name without its .tmpl extension,
+// with .txt added when nothing tells the output format.
+func templateName(templatePath string) string {
+	name := filepath.Base(templatePath)
+	for _, ext := range []string{".tmpl", ".tpl", ".gotmpl"} {
+		if strings.HasSuffix(name, ext) && len(name) > len(ext) {
+			name = strings.TrimSuffix(name, ext)
+			break
+		}
+	}
+	if filepath.Ext(name) == "" {
+		name += ".txt"
+	}
+	return name
+}
+
+// isHTMLTemplate reports whether the template renders HTML and so must be
+// executed with html/template's contextual escaping.
+func isHTMLTemplate(templatePath string) bool {
+	switch filepath.Ext(templateName(templatePath)) {
+	case ".html", ".htm", ".gohtml":
+		return true
+	}
+	return false
+}
+
+// GenerateTemplate renders findings with the user-supplied template at
+// templatePath into out. Templates producing HTML are executed with
+// html/template, everything else with text/template.
+func GenerateTemplate(findings []analyzer.Finding, meta Metadata, templatePath, out string) error {
+	src, err := os.ReadFile(templatePath)
+	if err != nil {
+		return err
+	}
+	data := TemplateData{
+		Findings:    findings,
+		Stats:       severityStats(findings),
+		Total:       len(findings),
+		Categories:  groupByCategory(findings),
+		Rules:       ruleStats(findings),
+		Scores:      computeScores(findings, meta),
+		Metadata:    meta,
+		GeneratedAt: time.Now(),
+	}
+
+	var execute func(io.Writer) error
+	name := filepath.Base(templatePath)
+	if isHTMLTemplate(templatePath) {
+		t, err := htmltemplate.New(name).Funcs(templateFuncs(meta)).Parse(string(src))
+		if err != nil {
+			return err
+		}
+		execute = func(w io.Writer) error { return t.Execute(w, data) }
+	} else {
+		t, err := texttemplate.New(name).Funcs(templateFuncs(meta)).Parse(string(src))
+		if err != nil {
+			return err
+		}
+		execute = func(w io.Writer) error { return t.Execute(w, data) }
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return execute(f)
+}
@@ -0,0 +1,84 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"strings"
+
+	"github.com/gotech-hub/gocheck/history"
+	"github.com/gotech-hub/gocheck/vcs"
+)
+
+const (
+	trendWidth   = 640
+	trendHeight  = 220
+	trendPadding = 36
+)
+
+// trendSeries are the lines drawn on the trend chart, using the same colors
+// as the severity badges.
+var trendSeries = []struct {
+	Name  string
+	Color string
+	Value func(history.Run) int
+}{
+	{"Total", "#333333", func(r history.Run) int { return r.Total }},
+	{"Critical", "#ff4d4f", func(r history.Run) int { return r.BySeverity["Critical"] }},
+	{"High", "#fa8c16", func(r history.Run) int { return r.BySeverity["High"] }},
+	{"Medium", "#fadb14", func(r history.Run) int { return r.BySeverity["Medium"] }},
+	{"Low", "#95de64", func(r history.Run) int { return r.BySeverity["Low"] }},
+}
+
+// trendChart renders the recorded runs as an inline SVG line chart, one line
+// per severity plus the total. It returns "" when there is no history.
+func trendChart(runs []history.Run) template.HTML {
+	if len(runs) == 0 {
+		return ""
+	}
+	maxValue := 1
+	for _, r := range runs {
+		maxValue = max(maxValue, r.Total)
+	}
+	plotW := float64(trendWidth - 2*trendPadding)
+	plotH := float64(trendHeight - 2*trendPadding)
+	x := func(i int) float64 {
+		if len(runs) == 1 {
+			return trendPadding + plotW/2
+		}
+		return trendPadding + plotW*float64(i)/float64(len(runs)-1)
+	}
+	y := func(v int) float64 {
+		return trendPadding + plotH - plotH*float64(v)/float64(maxValue)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg class="trend" viewBox="0 0 %d %d" width="100%%" xmlns="http://www.w3.org/2000/svg" font-size="11" font-family="Arial">`, trendWidth, trendHeight)
+	fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#ddd"/>`, trendPadding, y(0), trendWidth-trendPadding, y(0))
+	fmt.Fprintf(&b, `<text x="%d" y="%.1f" text-anchor="end" fill="#888">0</text>`, trendPadding-6, y(0)+4)
+	fmt.Fprintf(&b, `<text x="%d" y="%.1f" text-anchor="end" fill="#888">%d</text>`, trendPadding-6, y(maxValue)+4, maxValue)
+	fmt.Fprintf(&b, `<text x="%.1f" y="%d" fill="#888">%s</text>`, x(0), trendHeight-10, runs[0].Timestamp.Format("2006-01-02"))
+	if len(runs) > 1 {
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d" text-anchor="end" fill="#888">%s</text>`, x(len(runs)-1), trendHeight-10, runs[len(runs)-1].Timestamp.Format("2006-01-02"))
+	}
+
+	for si, s := range trendSeries {
+		var points []string
+		for i, r := range runs {
+			points = append(points, fmt.Sprintf("%.1f,%.1f", x(i), y(s.Value(r))))
+		}
+		fmt.Fprintf(&b, `<polyline fill="none" stroke="%s" stroke-width="2" points="%s"/>`, s.Color, strings.Join(points, " "))
+		for i, r := range runs {
+			label := r.Timestamp.Format("2006-01-02 15:04")
+			if r.Commit != "" {
+				label += " @ " + vcs.ShortHash(r.Commit)
+			}
+			fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="3" fill="%s"><title>%s: %d (%s)</title></circle>`,
+				x(i), y(s.Value(r)), s.Color, s.Name, s.Value(r), html.EscapeString(label))
+		}
+		fmt.Fprintf(&b, `<rect x="%d" y="8" width="10" height="10" fill="%s"/><text x="%d" y="17">%s</text>`,
+			trendPadding+si*90, s.Color, trendPadding+si*90+14, s.Name)
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
@@ -1,8 +1,11 @@
 package scanner
 
 import (
+	"bufio"
+	"bytes"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 func ScanDir(root string) []string {
@@ -15,3 +18,48 @@ func ScanDir(root string) []string {
 	})
 	return files
 }
+
+// CountLines returns the number of physical lines in each file. Files that
+// cannot be read are left out of the result.
+func CountLines(files []string) map[string]int {
+	lines := make(map[string]int, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		n := bytes.Count(data, []byte("\n"))
+		if len(data) > 0 && data[len(data)-1] != '\n' {
+			n++
+		}
+		lines[file] = n
+	}
+	return lines
+}
+
+// FindModule looks for go.mod in root and its parents and returns its
+// directory and module path, or empty strings if there is none.
+func FindModule(root string) (dir, path string) {
+	dir, err := filepath.Abs(root)
+	if err != nil {
+		return "", ""
+	}
+	for {
+		if f, err := os.Open(filepath.Join(dir, "go.mod")); err == nil {
+			defer f.Close()
+			sc := bufio.NewScanner(f)
+			for sc.Scan() {
+				line := strings.TrimSpace(sc.Text())
+				if strings.HasPrefix(line, "module ") {
+					return dir, strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module ")), `"`)
+				}
+			}
+			return "", ""
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ""
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,115 @@
+package vcs
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gotech-hub/gocheck/analyzer"
+)
+
+// blameWorkers bounds the number of concurrent git blame processes.
+const blameWorkers = 8
+
+// notCommitted is the hash git blame reports for lines changed in the
+// working tree.
+const notCommitted = "0000000000000000000000000000000000000000"
+
+// Blame returns the last commit that touched each line of file, keyed by
+// line number. It runs a single git blame for the whole file.
+func Blame(file string) (map[int]analyzer.Blame, error) {
+	cmd := exec.Command("git", "blame", "--line-porcelain", "--", filepath.Base(file))
+	cmd.Dir = filepath.Dir(file)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return parsePorcelain(out), nil
+}
+
+// parsePorcelain parses `git blame --line-porcelain`, which repeats the
+// commit information before every line of the file.
+func parsePorcelain(out []byte) map[int]analyzer.Blame {
+	lines := map[int]analyzer.Blame{}
+	var current analyzer.Blame
+	var lineNo int
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	header := true
+	for sc.Scan() {
+		text := sc.Text()
+		if header {
+			// "<hash> <original line> <final line> [<group size>]"
+			fields := strings.Fields(text)
+			if len(fields) < 3 {
+				continue
+			}
+			current = analyzer.Blame{Commit: fields[0]}
+			lineNo, _ = strconv.Atoi(fields[2])
+			header = false
+			continue
+		}
+		switch {
+		case strings.HasPrefix(text, "\t"):
+			if current.Commit == notCommitted {
+				current.Commit = ""
+				current.Author = "Not Committed Yet"
+				current.Date = time.Now()
+			}
+			lines[lineNo] = current
+			header = true
+		case strings.HasPrefix(text, "author "):
+			current.Author = strings.TrimPrefix(text, "author ")
+		case strings.HasPrefix(text, "author-mail "):
+			current.Email = strings.Trim(strings.TrimPrefix(text, "author-mail "), "<>")
+		case strings.HasPrefix(text, "author-time "):
+			if sec, err := strconv.ParseInt(strings.TrimPrefix(text, "author-time "), 10, 64); err == nil {
+				current.Date = time.Unix(sec, 0).UTC()
+			}
+		}
+	}
+	return lines
+}
+
+// AnnotateBlame sets the blame information of every finding whose file is
+// tracked by git. Each file is blamed once, however many findings it has,
+// and files are blamed concurrently.
+func AnnotateBlame(findings []analyzer.Finding) {
+	byFile := map[string][]int{}
+	for i, f := range findings {
+		if f.Line > 0 {
+			byFile[f.File] = append(byFile[f.File], i)
+		}
+	}
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for w := 0; w < blameWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range jobs {
+				blame, err := Blame(file)
+				if err != nil {
+					continue
+				}
+				// Each worker writes only the findings of its own file
+				for _, i := range byFile[file] {
+					if b, ok := blame[findings[i].Line]; ok {
+						findings[i].Blame = &b
+					}
+				}
+			}
+		}()
+	}
+	for file := range byFile {
+		jobs <- file
+	}
+	close(jobs)
+	wg.Wait()
+}
@@ -0,0 +1,62 @@
+package vcs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePorcelain(t *testing.T) {
+	out := "a1b2c3d4e5f60718293a4b5c6d7e8f9012345678 1 1 2\n" +
+		"author Alice Doe\n" +
+		"author-mail <alice@example.com>\n" +
+		"author-time 1700000000\n" +
+		"author-tz +0100\n" +
+		"summary first commit\n" +
+		"filename main.go\n" +
+		"\tpackage main\n" +
+		"a1b2c3d4e5f60718293a4b5c6d7e8f9012345678 2 2\n" +
+		"author Alice Doe\n" +
+		"author-mail <alice@example.com>\n" +
+		"author-time 1700000000\n" +
+		"filename main.go\n" +
+		"\t\n" +
+		"0000000000000000000000000000000000000000 5 3 1\n" +
+		"author Not Committed Yet\n" +
+		"author-mail <not.committed.yet>\n" +
+		"author-time 1800000000\n" +
+		"filename main.go\n" +
+		"\tfunc main() {}\n"
+
+	got := parsePorcelain([]byte(out))
+	tests := []struct {
+		line   int
+		commit string
+		author string
+		email  string
+		date   time.Time
+	}{
+		{1, "a1b2c3d4e5f60718293a4b5c6d7e8f9012345678", "Alice Doe", "alice@example.com", time.Unix(1700000000, 0).UTC()},
+		{2, "a1b2c3d4e5f60718293a4b5c6d7e8f9012345678", "Alice Doe", "alice@example.com", time.Unix(1700000000, 0).UTC()},
+		{3, "", "Not Committed Yet", "not.committed.yet", time.Time{}},
+	}
+	if len(got) != len(tests) {
+		t.Fatalf("got %d lines, want %d: %v", len(got), len(tests), got)
+	}
+	for _, tt := range tests {
+		b, ok := got[tt.line]
+		if !ok {
+			t.Errorf("line %d: missing", tt.line)
+			continue
+		}
+		if b.Commit != tt.commit || b.Author != tt.author || b.Email != tt.email {
+			t.Errorf("line %d: got %q %q %q, want %q %q %q", tt.line, b.Commit, b.Author, b.Email, tt.commit, tt.author, tt.email)
+		}
+		// uncommitted lines are dated now
+		if !tt.date.IsZero() && !b.Date.Equal(tt.date) {
+			t.Errorf("line %d: date %v, want %v", tt.line, b.Date, tt.date)
+		}
+		if tt.date.IsZero() && time.Since(b.Date) > time.Minute {
+			t.Errorf("line %d: date %v, want now", tt.line, b.Date)
+		}
+	}
+}
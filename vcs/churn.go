@@ -0,0 +1,119 @@
+package vcs
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultChurnSince is the default window of the git history used for churn.
+const DefaultChurnSince = "6 months ago"
+
+// FileChurn counts the commits that changed a file, and each function in it.
+type FileChurn struct {
+	Commits int
+	Funcs   map[string]int // keyed by "Name" or "Type.Method"
+}
+
+// Churn reads the git log of the repository containing dir since the given
+// date (any date git understands, e.g. "6 months ago" or "2024-01-01") and
+// counts how many commits changed each Go file below dir, keyed by absolute
+// path. Functions are recognised from the hunk headers git writes above each
+// change, so a commit counts once for every function it touched.
+func Churn(dir, since string) (map[string]*FileChurn, error) {
+	top, err := exec.Command("git", "-C", dir, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return nil, err
+	}
+	root := strings.TrimSpace(string(top))
+
+	cmd := exec.Command("git", "log", "--since="+since, "--no-merges", "--no-renames", "--no-color",
+		"-p", "-U0", "--format=%x00%H", "--", "*.go")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	churn := map[string]*FileChurn{}
+	var file *FileChurn
+	var path string
+	seen := map[string]bool{} // file and function keys already counted for the current commit
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		text := sc.Text()
+		switch {
+		case strings.HasPrefix(text, "\x00"):
+			seen = map[string]bool{}
+			file = nil
+		case strings.HasPrefix(text, "+++ "):
+			file = nil
+			name := strings.TrimPrefix(text, "+++ ")
+			if !strings.HasPrefix(name, "b/") {
+				continue // deleted file
+			}
+			path = filepath.Join(root, filepath.FromSlash(strings.TrimPrefix(name, "b/")))
+			file = churn[path]
+			if file == nil {
+				file = &FileChurn{Funcs: map[string]int{}}
+				churn[path] = file
+			}
+			if !seen[path] {
+				seen[path] = true
+				file.Commits++
+			}
+		case strings.HasPrefix(text, "@@ ") && file != nil:
+			// "@@ -a,b +c,d @@ func (r *T) Name(args) {"
+			parts := strings.SplitN(text, "@@", 3)
+			if len(parts) < 3 {
+				continue
+			}
+			fn := funcFromHeader(strings.TrimSpace(parts[2]))
+			if fn == "" {
+				continue
+			}
+			key := path + "\x00" + fn
+			if !seen[key] {
+				seen[key] = true
+				file.Funcs[fn]++
+			}
+		}
+	}
+	return churn, nil
+}
+
+// funcFromHeader extracts the function name from the context git prints
+// after a hunk header, e.g. "func (r *T) Name(args) {" gives "T.Name". It
+// returns "" when the context is not a function declaration.
+func funcFromHeader(header string) string {
+	rest, ok := strings.CutPrefix(header, "func ")
+	if !ok {
+		return ""
+	}
+	recv := ""
+	if strings.HasPrefix(rest, "(") {
+		end := strings.Index(rest, ")")
+		if end < 0 {
+			return ""
+		}
+		fields := strings.Fields(rest[1:end])
+		if len(fields) > 0 {
+			recv = strings.TrimPrefix(fields[len(fields)-1], "*")
+			if i := strings.Index(recv, "["); i >= 0 {
+				recv = recv[:i]
+			}
+		}
+		rest = strings.TrimSpace(rest[end+1:])
+	}
+	end := strings.IndexAny(rest, "([ ")
+	if end <= 0 {
+		return ""
+	}
+	if recv != "" {
+		return recv + "." + rest[:end]
+	}
+	return rest[:end]
+}
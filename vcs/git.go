@@ -0,0 +1,34 @@
+package vcs
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// Head returns the commit hash checked out in the git repository containing
+// dir, or "" if dir is not inside a git repository or git is unavailable.
+func Head(dir string) string {
+	return revParse(dir, "HEAD")
+}
+
+// TopLevel returns the top-level directory of the git repository containing
+// dir, or "" if dir is not inside a git repository or git is unavailable.
+func TopLevel(dir string) string {
+	return revParse(dir, "--show-toplevel")
+}
+
+// ShortHash abbreviates a commit hash to its first 8 characters for display.
+func ShortHash(commit string) string {
+	if len(commit) > 8 {
+		return commit[:8]
+	}
+	return commit
+}
+
+func revParse(dir, arg string) string {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", arg).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}